@@ -1,14 +1,22 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"os/signal"
+	"regexp"
 	"syscall"
 
+	"kafka-admin-api/internal/audit"
+	"kafka-admin-api/internal/auth"
 	"kafka-admin-api/internal/config"
 	"kafka-admin-api/internal/handler"
 	"kafka-admin-api/internal/kafka"
+	"kafka-admin-api/internal/lag"
+	"kafka-admin-api/internal/metrics"
+	"kafka-admin-api/internal/schemaregistry"
+	"kafka-admin-api/internal/topicpolicy"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -22,11 +30,26 @@ func main() {
 		os.Exit(1)
 	}
 
+	var registry *schemaregistry.Client
+	if cfg.SchemaRegistryURL != "" {
+		registry = schemaregistry.NewClient(schemaregistry.Config{
+			URL:      cfg.SchemaRegistryURL,
+			Username: cfg.SchemaRegistryUsername,
+			Password: cfg.SchemaRegistryPassword,
+		})
+		logger.Info("schema registry decoding enabled", "url", cfg.SchemaRegistryURL)
+	}
+
+	stats := metrics.New()
+
 	kafkaClient, err := kafka.NewClient(kafka.Config{
-		BootstrapServers: cfg.BootstrapServers,
-		Username:         cfg.SASLUsername,
-		Password:         cfg.SASLPassword,
-		CALocation:       cfg.CALocation,
+		BootstrapServers:    cfg.BootstrapServers,
+		Username:            cfg.SASLUsername,
+		Password:            cfg.SASLPassword,
+		CALocation:          cfg.CALocation,
+		ProducerAcks:        cfg.ProducerAcks,
+		ProducerIdempotence: cfg.ProducerIdempotence,
+		Observer:            stats,
 	}, logger)
 	if err != nil {
 		logger.Error("failed to create kafka client", "error", err)
@@ -34,11 +57,124 @@ func main() {
 	}
 	defer kafkaClient.Close()
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var evaluator *lag.Evaluator
+	if cfg.LagExporterEnabled {
+		evaluator = lag.NewEvaluator(cfg.LagHealthWindow)
+		lagConfig := lag.Config{
+			Interval:    cfg.LagExporterInterval,
+			Concurrency: cfg.LagExporterConcurrency,
+			Evaluator:   evaluator,
+		}
+		if lagConfig.Include, err = compilePattern(cfg.LagExporterInclude); err != nil {
+			logger.Error("invalid LAG_EXPORTER_GROUP_INCLUDE", "error", err)
+			os.Exit(1)
+		}
+		if lagConfig.Exclude, err = compilePattern(cfg.LagExporterExclude); err != nil {
+			logger.Error("invalid LAG_EXPORTER_GROUP_EXCLUDE", "error", err)
+			os.Exit(1)
+		}
+
+		exporter := lag.NewExporter(kafkaClient, lagConfig, logger)
+		stats.Registry().MustRegister(exporter)
+		go exporter.Run(ctx)
+		logger.Info("consumer lag exporter enabled", "interval", lagConfig.Interval)
+	}
+
+	var keys *auth.KeyStore
+	if cfg.APIKeysFile != "" {
+		keys, err = auth.LoadKeyStore(cfg.APIKeysFile, logger)
+		if err != nil {
+			logger.Error("failed to load api keys", "error", err)
+			os.Exit(1)
+		}
+		go keys.Watch(ctx, cfg.APIKeysReloadInterval)
+	}
+
+	var verifier *auth.JWTVerifier
+	if cfg.JWTJWKSURL != "" || cfg.JWTJWKSFile != "" {
+		groupScopes, err := auth.ParseGroupScopes(cfg.JWTGroupScopes)
+		if err != nil {
+			logger.Error("invalid JWT_GROUP_SCOPES", "error", err)
+			os.Exit(1)
+		}
+		verifier, err = auth.NewJWTVerifier(ctx, auth.JWTConfig{
+			Issuer:       cfg.JWTIssuer,
+			Audience:     cfg.JWTAudience,
+			JWKSURL:      cfg.JWTJWKSURL,
+			JWKSFile:     cfg.JWTJWKSFile,
+			SubjectClaim: cfg.JWTSubjectClaim,
+			GroupsClaim:  cfg.JWTGroupsClaim,
+			GroupScopes:  groupScopes,
+		}, logger)
+		if err != nil {
+			logger.Error("failed to set up JWT authentication", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	if keys == nil && verifier == nil {
+		logger.Warn("neither API_KEYS_FILE nor a JWKS source is set, authentication is disabled and admin endpoints are refused")
+	}
+
+	var policy *auth.Policy
+	if cfg.PolicyFile != "" {
+		if keys == nil && verifier == nil {
+			logger.Error("AUTHZ_POLICY_FILE requires API keys or JWT authentication")
+			os.Exit(1)
+		}
+		policy, err = auth.LoadPolicy(cfg.PolicyFile)
+		if err != nil {
+			logger.Error("failed to load authorization policy", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("authorization policy loaded", "rules", len(policy.Rules))
+	}
+
+	var topicPolicy *topicpolicy.Policy
+	if cfg.TopicPolicyFile != "" {
+		topicPolicy, err = topicpolicy.Load(cfg.TopicPolicyFile)
+		if err != nil {
+			logger.Error("failed to load topic policy", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("topic policy loaded", "path", cfg.TopicPolicyFile)
+	}
+
+	var auditLog *audit.Log
+	if cfg.AuditLogFile != "" {
+		auditLog, err = audit.Open(audit.Config{
+			Path:       cfg.AuditLogFile,
+			Topic:      cfg.AuditTopic,
+			Producer:   kafkaClient,
+			BufferSize: cfg.AuditBufferSize,
+		}, logger)
+		if err != nil {
+			logger.Error("failed to open audit log", "error", err)
+			os.Exit(1)
+		}
+		defer auditLog.Close()
+		logger.Info("audit log enabled", "path", cfg.AuditLogFile, "topic", cfg.AuditTopic)
+	}
+
+	h := handler.New(kafkaClient, logger,
+		handler.WithSchemaRegistry(registry),
+		handler.WithMetrics(stats),
+		handler.WithLagEvaluator(evaluator),
+		handler.WithReadiness(cfg.ExpectedBrokers, cfg.ReadinessTimeout),
+		handler.WithAPIKeys(keys),
+		handler.WithJWT(verifier),
+		handler.WithPolicy(policy),
+		handler.WithAudit(auditLog),
+		handler.WithTopicPolicy(topicPolicy),
+	)
+
 	app := fiber.New(fiber.Config{
-		AppName: "kafka-admin-api",
+		AppName:      "kafka-admin-api",
+		ErrorHandler: h.ErrorHandler,
 	})
-
-	h := handler.New(kafkaClient, logger)
 	h.SetupRoutes(app)
 
 	// Graceful shutdown
@@ -48,6 +184,7 @@ func main() {
 		<-sigChan
 
 		logger.Info("shutting down server")
+		cancel()
 		if err := app.Shutdown(); err != nil {
 			logger.Error("shutdown error", "error", err)
 		}
@@ -59,3 +196,10 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	return regexp.Compile(pattern)
+}
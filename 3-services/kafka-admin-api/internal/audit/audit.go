@@ -0,0 +1,228 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"kafka-admin-api/internal/model"
+)
+
+// publishQueueSize bounds the events waiting to be published to Kafka.
+// Events beyond it are dropped from the topic but still written to the file.
+const publishQueueSize = 1000
+
+// Producer publishes events to Kafka; the Kafka client satisfies it.
+type Producer interface {
+	Produce(ctx context.Context, topic string, messages []model.ProduceMessage) ([]model.ProduceResult, error)
+}
+
+type Config struct {
+	// Path of the JSON-lines file events are appended to.
+	Path string
+	// Topic, when set together with Producer, receives a copy of every
+	// event keyed by resource name.
+	Topic    string
+	Producer Producer
+	// BufferSize is the number of recent events kept for Query.
+	BufferSize int
+}
+
+// Filter selects events in Query. Zero fields match everything.
+type Filter struct {
+	Principal    string
+	ResourceType string
+	Resource     string
+	Action       string
+	Since        time.Time
+	Limit        int
+}
+
+// Log appends audit events to a file, optionally publishes them to Kafka
+// and keeps the most recent ones in memory for querying.
+type Log struct {
+	config Config
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	file   *os.File
+	recent []model.AuditEvent
+	next   int
+	full   bool
+	closed bool
+
+	queue     chan message
+	publisher sync.WaitGroup
+}
+
+// message is an encoded event waiting to be published.
+type message struct {
+	key, value string
+}
+
+// Open opens or creates the audit file and loads its most recent events.
+func Open(cfg Config, logger *slog.Logger) (*Log, error) {
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 1000
+	}
+	l := &Log{
+		config: cfg,
+		logger: logger,
+		recent: make([]model.AuditEvent, cfg.BufferSize),
+	}
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	l.file = file
+
+	if cfg.Producer != nil && cfg.Topic != "" {
+		l.queue = make(chan message, publishQueueSize)
+		l.publisher.Add(1)
+		go l.publishQueued()
+	}
+	return l, nil
+}
+
+func (l *Log) load() error {
+	file, err := os.Open(l.config.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var event model.AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			l.logger.Warn("skipping malformed audit log line", "path", l.config.Path, "error", err)
+			continue
+		}
+		l.remember(event)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read audit log: %w", err)
+	}
+	return nil
+}
+
+// Record appends event to the file and publishes it. Failures are logged
+// rather than returned so auditing never fails the operation it records.
+func (l *Log) Record(event model.AuditEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		l.logger.Error("encode audit event", "action", event.Action, "resource", event.Resource, "error", err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		l.logger.Error("audit log is closed, dropping event", "action", event.Action, "resource", event.Resource)
+		return
+	}
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		l.logger.Error("write audit event", "path", l.config.Path, "error", err)
+	}
+	l.remember(event)
+
+	if l.queue != nil {
+		select {
+		case l.queue <- message{key: event.Resource, value: string(line)}:
+		default:
+			l.logger.Error("audit publish queue is full, dropping event",
+				"topic", l.config.Topic, "action", event.Action, "resource", event.Resource)
+		}
+	}
+}
+
+// publishQueued publishes queued events one at a time until Close closes
+// the queue.
+func (l *Log) publishQueued() {
+	defer l.publisher.Done()
+	for m := range l.queue {
+		l.publish(m.key, m.value)
+	}
+}
+
+func (l *Log) publish(key, value string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	results, err := l.config.Producer.Produce(ctx, l.config.Topic, []model.ProduceMessage{{Key: &key, Value: &value}})
+	if err == nil && len(results) > 0 && results[0].Error != "" {
+		err = fmt.Errorf("%s", results[0].Error)
+	}
+	if err != nil {
+		l.logger.Error("publish audit event", "topic", l.config.Topic, "error", err)
+	}
+}
+
+// remember adds event to the ring buffer; the caller holds mu or owns l.
+func (l *Log) remember(event model.AuditEvent) {
+	l.recent[l.next] = event
+	l.next = (l.next + 1) % len(l.recent)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Query returns the buffered events matching f, newest first.
+func (l *Log) Query(f Filter) []model.AuditEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	count := l.next
+	if l.full {
+		count = len(l.recent)
+	}
+
+	events := make([]model.AuditEvent, 0)
+	for i := 1; i <= count; i++ {
+		event := l.recent[(l.next-i+len(l.recent))%len(l.recent)]
+		if !f.matches(event) {
+			continue
+		}
+		events = append(events, event)
+		if f.Limit > 0 && len(events) == f.Limit {
+			break
+		}
+	}
+	return events
+}
+
+func (f Filter) matches(e model.AuditEvent) bool {
+	return (f.Principal == "" || e.Principal == f.Principal) &&
+		(f.ResourceType == "" || e.ResourceType == f.ResourceType) &&
+		(f.Resource == "" || e.Resource == f.Resource) &&
+		(f.Action == "" || e.Action == f.Action) &&
+		(f.Since.IsZero() || !e.Time.Before(f.Since))
+}
+
+// Close stops accepting events, waits until the queued ones are published
+// and closes the file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	l.closed = true
+	if l.queue != nil {
+		close(l.queue)
+	}
+	err := l.file.Close()
+	l.mu.Unlock()
+
+	l.publisher.Wait()
+	return err
+}
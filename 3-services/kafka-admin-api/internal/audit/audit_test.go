@@ -0,0 +1,157 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kafka-admin-api/internal/model"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type recordingProducer struct {
+	mu       sync.Mutex
+	topic    string
+	messages []model.ProduceMessage
+	done     chan struct{}
+}
+
+func (p *recordingProducer) Produce(_ context.Context, topic string, messages []model.ProduceMessage) ([]model.ProduceResult, error) {
+	p.mu.Lock()
+	p.topic = topic
+	p.messages = append(p.messages, messages...)
+	p.mu.Unlock()
+	p.done <- struct{}{}
+	return []model.ProduceResult{{}}, nil
+}
+
+func event(action, resource string, at time.Time) model.AuditEvent {
+	return model.AuditEvent{
+		Time:         at,
+		Principal:    "ci",
+		Action:       action,
+		ResourceType: "topic",
+		Resource:     resource,
+		Outcome:      model.AuditSuccess,
+	}
+}
+
+func TestLogRecordAppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(Config{Path: path}, discard)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	log.Record(event("create", "orders", now))
+	log.Record(event("alter", "orders", now))
+	require.NoError(t, log.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var first model.AuditEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "create", first.Action)
+	assert.Equal(t, "orders", first.Resource)
+}
+
+func TestLogQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(Config{Path: path, BufferSize: 3}, discard)
+	require.NoError(t, err)
+	defer log.Close()
+
+	start := time.Now().UTC()
+	log.Record(event("create", "a", start))
+	log.Record(event("create", "b", start.Add(time.Second)))
+	log.Record(event("alter", "b", start.Add(2*time.Second)))
+	log.Record(event("delete", "c", start.Add(3*time.Second)))
+
+	all := log.Query(Filter{})
+	require.Len(t, all, 3, "oldest event is evicted from the buffer")
+	assert.Equal(t, "c", all[0].Resource, "newest first")
+	assert.Equal(t, "b", all[2].Resource)
+
+	assert.Len(t, log.Query(Filter{Resource: "b"}), 2)
+	assert.Len(t, log.Query(Filter{Action: "delete"}), 1)
+	assert.Len(t, log.Query(Filter{Since: start.Add(2 * time.Second)}), 2)
+	assert.Len(t, log.Query(Filter{Limit: 1}), 1)
+	assert.Empty(t, log.Query(Filter{Principal: "someone-else"}))
+}
+
+func TestOpenLoadsExistingEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := Open(Config{Path: path}, discard)
+	require.NoError(t, err)
+	log.Record(event("create", "orders", time.Now().UTC()))
+	require.NoError(t, log.Close())
+
+	reopened, err := Open(Config{Path: path}, discard)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	events := reopened.Query(Filter{})
+	require.Len(t, events, 1)
+	assert.Equal(t, "orders", events[0].Resource)
+}
+
+func TestLogPublishesToTopic(t *testing.T) {
+	producer := &recordingProducer{done: make(chan struct{}, 1)}
+	log, err := Open(Config{
+		Path:     filepath.Join(t.TempDir(), "audit.jsonl"),
+		Topic:    "_audit",
+		Producer: producer,
+	}, discard)
+	require.NoError(t, err)
+	defer log.Close()
+
+	log.Record(event("delete", "orders", time.Now().UTC()))
+
+	select {
+	case <-producer.done:
+	case <-time.After(time.Second):
+		t.Fatal("event was not published")
+	}
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	assert.Equal(t, "_audit", producer.topic)
+	require.Len(t, producer.messages, 1)
+	assert.Equal(t, "orders", *producer.messages[0].Key)
+	assert.Contains(t, *producer.messages[0].Value, `"action":"delete"`)
+}
+
+func TestLogCloseDrainsPublishQueue(t *testing.T) {
+	producer := &recordingProducer{done: make(chan struct{}, 10)}
+	log, err := Open(Config{
+		Path:     filepath.Join(t.TempDir(), "audit.jsonl"),
+		Topic:    "_audit",
+		Producer: producer,
+	}, discard)
+	require.NoError(t, err)
+
+	for _, name := range []string{"a", "b", "c"} {
+		log.Record(event("create", name, time.Now().UTC()))
+	}
+	require.NoError(t, log.Close())
+
+	producer.mu.Lock()
+	defer producer.mu.Unlock()
+	require.Len(t, producer.messages, 3, "Close returns only after every queued event is published")
+	assert.Equal(t, "c", *producer.messages[2].Key, "events are published in order")
+
+	log.Record(event("delete", "d", time.Now().UTC()))
+	assert.Len(t, producer.messages, 3, "events recorded after Close are dropped")
+}
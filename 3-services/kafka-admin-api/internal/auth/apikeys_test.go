@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeys(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestKeyStoreAuthenticate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	writeKeys(t, path, `
+keys:
+  - name: ci
+    hash: `+HashKey("secret-ci")+`
+    scopes: [read-metadata, manage-topics]
+`)
+
+	store, err := LoadKeyStore(path, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	p, ok := store.Authenticate("secret-ci")
+	assert.True(t, ok)
+	assert.Equal(t, "ci", p.Name)
+	assert.True(t, p.HasScope(ScopeManageTopics))
+	assert.False(t, p.HasScope(ScopeProduce))
+
+	_, ok = store.Authenticate("wrong")
+	assert.False(t, ok)
+}
+
+func TestParseKeysRejectsInvalidEntries(t *testing.T) {
+	tests := map[string]string{
+		"plaintext key": "keys:\n  - name: ci\n    hash: secret-ci\n",
+		"short digest":  "keys:\n  - name: ci\n    hash: sha256:abcd\n",
+		"unknown scope": "keys:\n  - name: ci\n    hash: " + HashKey("x") + "\n    scopes: [admin]\n",
+		"missing name":  "keys:\n  - hash: " + HashKey("x") + "\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseKeys([]byte(content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyStoreWatchReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	writeKeys(t, path, "keys:\n  - name: old\n    hash: "+HashKey("old-key")+"\n")
+
+	store, err := LoadKeyStore(path, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Watch(ctx, 10*time.Millisecond)
+
+	writeKeys(t, path, "keys:\n  - name: new\n    hash: "+HashKey("new-key")+"\n")
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+
+	assert.Eventually(t, func() bool {
+		_, ok := store.Authenticate("new-key")
+		return ok
+	}, time.Second, 10*time.Millisecond)
+	_, ok := store.Authenticate("old-key")
+	assert.False(t, ok)
+
+	// A broken file keeps the last good keys.
+	writeKeys(t, path, "keys: [")
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(2*time.Second)))
+	time.Sleep(50 * time.Millisecond)
+	_, ok = store.Authenticate("new-key")
+	assert.True(t, ok)
+}
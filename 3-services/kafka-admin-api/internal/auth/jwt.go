@@ -0,0 +1,332 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minJWKSRefresh rate-limits JWKS refreshes triggered by unknown key IDs.
+const minJWKSRefresh = time.Minute
+
+// JWTConfig configures bearer token validation. Issuer and Audience are
+// required: a JWKS alone would accept tokens the provider issued to any of
+// its clients.
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	// Exactly one of JWKSURL and JWKSFile is set.
+	JWKSURL  string
+	JWKSFile string
+	// SubjectClaim names the claim identifying the user, "sub" by default.
+	// It must be stable and not editable by the user.
+	SubjectClaim string
+	// GroupsClaim names the claim listing the user's groups or roles.
+	GroupsClaim string
+	// GroupScopes grants scopes to members of each group.
+	GroupScopes map[string][]Scope
+}
+
+// JWTVerifier validates bearer tokens against a JWKS and maps their groups
+// to scopes.
+type JWTVerifier struct {
+	config JWTConfig
+	logger *slog.Logger
+	http   *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+func NewJWTVerifier(ctx context.Context, cfg JWTConfig, logger *slog.Logger) (*JWTVerifier, error) {
+	if (cfg.JWKSURL == "") == (cfg.JWKSFile == "") {
+		return nil, errors.New("exactly one of JWKS URL and JWKS file is required")
+	}
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("issuer and audience are required")
+	}
+	if cfg.SubjectClaim == "" {
+		cfg.SubjectClaim = "sub"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+
+	v := &JWTVerifier{
+		config: cfg,
+		logger: logger,
+		http:   &http.Client{Timeout: 10 * time.Second},
+	}
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Verify validates token and returns its principal, named by the subject
+// claim. Display claims such as preferred_username or email are not used,
+// as many providers let users change them.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+		jwt.WithIssuer(v.config.Issuer),
+		jwt.WithAudience(v.config.Audience),
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims[v.config.SubjectClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("token has no %s claim", v.config.SubjectClaim)
+	}
+	principal := &Principal{Kind: PrincipalUser, Name: subject, Groups: stringsClaim(claims[v.config.GroupsClaim])}
+
+	seen := make(map[Scope]bool)
+	for _, group := range principal.Groups {
+		for _, scope := range v.config.GroupScopes[group] {
+			if !seen[scope] {
+				seen[scope] = true
+				principal.Scopes = append(principal.Scopes, scope)
+			}
+		}
+	}
+	return principal, nil
+}
+
+// key returns the key for kid. An unknown kid triggers a JWKS refresh, at
+// most once per minJWKSRefresh, to pick up rotated keys.
+func (v *JWTVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+
+	v.mu.RLock()
+	stale := time.Since(v.lastRefresh) > minJWKSRefresh
+	v.mu.RUnlock()
+	if stale {
+		if err := v.refresh(ctx); err != nil {
+			v.logger.Error("refresh jwks", "error", err)
+		}
+		if key, ok := v.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup matches kid, or the only key of the set when the token has no kid.
+func (v *JWTVerifier) lookup(kid string) (crypto.PublicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+func (v *JWTVerifier) refresh(ctx context.Context) error {
+	data, err := v.fetchJWKS(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("parse jwks: %w", err)
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.lastRefresh = time.Now()
+	v.mu.Unlock()
+
+	v.logger.Info("jwks loaded", "keys", len(keys))
+	return nil
+}
+
+func (v *JWTVerifier) fetchJWKS(ctx context.Context) ([]byte, error) {
+	if v.config.JWKSFile != "" {
+		data, err := os.ReadFile(v.config.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwks file: %w", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.config.JWKSURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwks request: %w", err)
+	}
+	resp, err := v.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwks request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwks request: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS reads the RSA and EC signing keys of a JWK set. Other key types
+// and encryption keys are skipped.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k)
+		case "EC":
+			key, err = ecKey(k)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no usable signing keys")
+	}
+	return keys, nil
+}
+
+func rsaKey(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode n: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode e: %w", err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func ecKey(k jwk) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+	x, err := base64.RawURLEncoding.DecodeString(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("decode x: %w", err)
+	}
+	y, err := base64.RawURLEncoding.DecodeString(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("decode y: %w", err)
+	}
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
+
+// stringsClaim reads a claim holding a string or a list of strings.
+func stringsClaim(v any) []string {
+	switch c := v.(type) {
+	case string:
+		return []string{c}
+	case []any:
+		out := make([]string, 0, len(c))
+		for _, item := range c {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// ParseGroupScopes parses "group=scope,scope;group=scope" as used by
+// JWT_GROUP_SCOPES.
+func ParseGroupScopes(s string) (map[string][]Scope, error) {
+	mapping := make(map[string][]Scope)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, scopes, ok := strings.Cut(entry, "=")
+		group = strings.TrimSpace(group)
+		if !ok || group == "" {
+			return nil, fmt.Errorf("invalid group mapping %q, want group=scope,scope", entry)
+		}
+		for _, scope := range strings.Split(scopes, ",") {
+			scope := Scope(strings.TrimSpace(scope))
+			if !validScopes[scope] {
+				return nil, fmt.Errorf("group %s: unknown scope %q", group, scope)
+			}
+			mapping[group] = append(mapping[group], scope)
+		}
+	}
+	return mapping, nil
+}
@@ -0,0 +1,155 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJWKS(t *testing.T, rsaKey *rsa.PrivateKey, ecKey *ecdsa.PrivateKey) string {
+	t.Helper()
+	set := map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA", "kid": "rsa-1", "use": "sig",
+			"n": b64(rsaKey.N.Bytes()),
+			"e": b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		},
+		{
+			"kty": "EC", "kid": "ec-1", "crv": "P-256",
+			"x": b64(ecKey.X.FillBytes(make([]byte, 32))),
+			"y": b64(ecKey.Y.FillBytes(make([]byte, 32))),
+		},
+		{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+	}}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestJWTVerifier(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	verifier, err := NewJWTVerifier(context.Background(), JWTConfig{
+		Issuer:   "https://sso.example.com",
+		Audience: "kafka-admin-api",
+		JWKSFile: writeJWKS(t, rsaKey, ecKey),
+		GroupScopes: map[string][]Scope{
+			"kafka-readers": {ScopeReadMetadata, ScopeReadMessages},
+			"kafka-admins":  {ScopeReadMetadata, ScopeManageTopics},
+		},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":                "https://sso.example.com",
+			"aud":                "kafka-admin-api",
+			"sub":                "4f1c",
+			"preferred_username": "alice",
+			"groups":             []string{"kafka-readers", "kafka-admins"},
+			"exp":                time.Now().Add(time.Hour).Unix(),
+		}
+	}
+
+	principal, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, valid()))
+	require.NoError(t, err)
+	assert.Equal(t, "4f1c", principal.Name)
+	assert.Equal(t, []string{"kafka-readers", "kafka-admins"}, principal.Groups)
+	assert.ElementsMatch(t, []Scope{ScopeReadMetadata, ScopeReadMessages, ScopeManageTopics}, principal.Scopes)
+
+	_, err = verifier.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "ec-1", ecKey, valid()))
+	assert.NoError(t, err)
+
+	rejected := map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other-api" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+		"no subject":     func(c jwt.MapClaims) { delete(c, "sub") },
+		"no audience":    func(c jwt.MapClaims) { delete(c, "aud") },
+	}
+	for name, mutate := range rejected {
+		t.Run(name, func(t *testing.T) {
+			claims := valid()
+			mutate(claims)
+			_, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, claims))
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = verifier.Verify(context.Background(), sign(t, jwt.SigningMethodRS256, "rsa-2", other, valid()))
+		assert.ErrorContains(t, err, "unknown signing key")
+	})
+
+	t.Run("symmetric algorithm", func(t *testing.T) {
+		_, err := verifier.Verify(context.Background(), sign(t, jwt.SigningMethodHS256, "hmac", []byte("secret"), valid()))
+		assert.Error(t, err)
+	})
+}
+
+func TestJWTVerifierRequiresIssuerAndAudience(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwks := writeJWKS(t, rsaKey, ecKey)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, cfg := range []JWTConfig{
+		{JWKSFile: jwks},
+		{JWKSFile: jwks, Issuer: "https://sso.example.com"},
+		{JWKSFile: jwks, Audience: "kafka-admin-api"},
+	} {
+		_, err := NewJWTVerifier(context.Background(), cfg, logger)
+		assert.ErrorContains(t, err, "issuer and audience are required")
+	}
+}
+
+func TestParseGroupScopes(t *testing.T) {
+	mapping, err := ParseGroupScopes("kafka-admins=read-metadata, manage-topics; kafka-readers=read-messages")
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeReadMetadata, ScopeManageTopics}, mapping["kafka-admins"])
+	assert.Equal(t, []Scope{ScopeReadMessages}, mapping["kafka-readers"])
+
+	_, err = ParseGroupScopes("kafka-admins=superuser")
+	assert.Error(t, err)
+	_, err = ParseGroupScopes("read-metadata")
+	assert.Error(t, err)
+}
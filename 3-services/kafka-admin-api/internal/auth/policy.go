@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Action is an operation on a topic or consumer group resource.
+type Action string
+
+const (
+	ActionDescribe     Action = "describe"
+	ActionCreate       Action = "create"
+	ActionAlter        Action = "alter"
+	ActionDelete       Action = "delete"
+	ActionConsume      Action = "consume"
+	ActionProduce      Action = "produce"
+	ActionResetOffsets Action = "reset-offsets"
+)
+
+var validActions = map[Action]bool{
+	ActionDescribe:     true,
+	ActionCreate:       true,
+	ActionAlter:        true,
+	ActionDelete:       true,
+	ActionConsume:      true,
+	ActionProduce:      true,
+	ActionResetOffsets: true,
+}
+
+// ResourceType is the kind of resource a rule applies to.
+type ResourceType string
+
+const (
+	ResourceTopic ResourceType = "topic"
+	ResourceGroup ResourceType = "group"
+	// The remaining types only appear in audit events; policy rules cannot
+	// grant them.
+	ResourceACL     ResourceType = "acl"
+	ResourceUser    ResourceType = "user"
+	ResourceQuota   ResourceType = "quota"
+	ResourceBroker  ResourceType = "broker"
+	ResourceCluster ResourceType = "cluster"
+)
+
+const (
+	PatternLiteral = "literal"
+	PatternPrefix  = "prefix"
+)
+
+// Rule grants Actions on the resources matching Name to Principals. A
+// principal entry is "key:<name>" for an API key, "user:<subject>" for a
+// token user, "group:<name>" for members of a token group, or "*" for
+// every authenticated caller.
+type Rule struct {
+	Principals  []string     `yaml:"principals"`
+	Resource    ResourceType `yaml:"resource"`
+	Name        string       `yaml:"name"`
+	PatternType string       `yaml:"pattern_type"`
+	Actions     []Action     `yaml:"actions"`
+}
+
+// Policy is an allow-list of rules; anything not granted is denied.
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// LoadPolicy reads and validates a YAML policy file:
+//
+//	rules:
+//	  - principals: ["group:payments-team", "key:ci"]
+//	    resource: topic
+//	    name: payments.
+//	    pattern_type: prefix
+//	    actions: [describe, create, alter, produce, consume]
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("parse policy file %s: %w", path, err)
+	}
+	for i := range policy.Rules {
+		if err := policy.Rules[i].validate(); err != nil {
+			return nil, fmt.Errorf("policy rule %d: %w", i, err)
+		}
+	}
+	return &policy, nil
+}
+
+func (r *Rule) validate() error {
+	if len(r.Principals) == 0 {
+		return fmt.Errorf("principals required")
+	}
+	for _, p := range r.Principals {
+		if !validPrincipalEntry(p) {
+			return fmt.Errorf("principal %q must be *, key:<name>, user:<subject> or group:<name>", p)
+		}
+	}
+	if r.Resource != ResourceTopic && r.Resource != ResourceGroup {
+		return fmt.Errorf("resource must be %s or %s", ResourceTopic, ResourceGroup)
+	}
+	if r.PatternType == "" {
+		r.PatternType = PatternLiteral
+	}
+	if r.PatternType != PatternLiteral && r.PatternType != PatternPrefix {
+		return fmt.Errorf("pattern_type must be %s or %s", PatternLiteral, PatternPrefix)
+	}
+	if r.Name == "" && r.PatternType == PatternLiteral {
+		return fmt.Errorf("name required for literal rules")
+	}
+	for _, a := range r.Actions {
+		if !validActions[a] {
+			return fmt.Errorf("unknown action %q", a)
+		}
+	}
+	return nil
+}
+
+// Allowed reports whether principal may perform action on the named resource.
+func (p *Policy) Allowed(principal *Principal, resource ResourceType, name string, action Action) bool {
+	if principal == nil {
+		return false
+	}
+	for _, r := range p.Rules {
+		if r.Resource == resource && r.matchesName(name) && r.grants(action) && r.appliesTo(principal) {
+			return true
+		}
+	}
+	return false
+}
+
+func validPrincipalEntry(entry string) bool {
+	if entry == "*" {
+		return true
+	}
+	kind, name, ok := strings.Cut(entry, ":")
+	if !ok || name == "" {
+		return false
+	}
+	return kind == PrincipalKey || kind == PrincipalUser || kind == "group"
+}
+
+func (r *Rule) matchesName(name string) bool {
+	if r.PatternType == PatternPrefix {
+		return strings.HasPrefix(name, r.Name)
+	}
+	return name == r.Name
+}
+
+func (r *Rule) grants(action Action) bool {
+	for _, a := range r.Actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) appliesTo(principal *Principal) bool {
+	for _, entry := range r.Principals {
+		if entry == "*" || entry == principal.ID() {
+			return true
+		}
+		if group, ok := strings.CutPrefix(entry, "group:"); ok {
+			for _, g := range principal.Groups {
+				if g == group {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyAllowed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	writeKeys(t, path, `
+rules:
+  - principals: ["group:payments"]
+    resource: topic
+    name: payments.
+    pattern_type: prefix
+    actions: [describe, produce]
+  - principals: ["key:ci"]
+    resource: group
+    name: ci-consumer
+    actions: [describe, reset-offsets]
+  - principals: ["user:ci"]
+    resource: group
+    name: sso-consumer
+    actions: [describe]
+  - principals: ["*"]
+    resource: topic
+    name: public
+    actions: [describe]
+`)
+	policy, err := LoadPolicy(path)
+	require.NoError(t, err)
+
+	alice := &Principal{Kind: PrincipalUser, Name: "alice", Groups: []string{"payments"}}
+	ci := &Principal{Kind: PrincipalKey, Name: "ci"}
+	ciUser := &Principal{Kind: PrincipalUser, Name: "ci"}
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		resource  ResourceType
+		resName   string
+		action    Action
+		want      bool
+	}{
+		{"prefix match via group", alice, ResourceTopic, "payments.orders", ActionProduce, true},
+		{"action not granted", alice, ResourceTopic, "payments.orders", ActionDelete, false},
+		{"prefix mismatch", alice, ResourceTopic, "billing.orders", ActionDescribe, false},
+		{"literal match by name", ci, ResourceGroup, "ci-consumer", ActionResetOffsets, true},
+		{"key rule does not match user of same name", ciUser, ResourceGroup, "ci-consumer", ActionResetOffsets, false},
+		{"user rule does not match key of same name", ci, ResourceGroup, "sso-consumer", ActionDescribe, false},
+		{"literal is not a prefix", ci, ResourceGroup, "ci-consumer-2", ActionDescribe, false},
+		{"resource type must match", ci, ResourceTopic, "ci-consumer", ActionDescribe, false},
+		{"wildcard principal", ci, ResourceTopic, "public", ActionDescribe, true},
+		{"no principal", nil, ResourceTopic, "public", ActionDescribe, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Allowed(tt.principal, tt.resource, tt.resName, tt.action))
+		})
+	}
+}
+
+func TestLoadPolicyRejectsInvalidRules(t *testing.T) {
+	tests := map[string]string{
+		"missing principals": "rules:\n  - resource: topic\n    name: a\n    actions: [describe]\n",
+		"unknown resource":   "rules:\n  - principals: [a]\n    resource: cluster\n    name: a\n",
+		"unknown pattern":    "rules:\n  - principals: [a]\n    resource: topic\n    name: a\n    pattern_type: regex\n",
+		"empty literal":      "rules:\n  - principals: [a]\n    resource: topic\n",
+		"unknown action":     "rules:\n  - principals: [a]\n    resource: topic\n    name: a\n    actions: [write]\n",
+		"bare principal":     "rules:\n  - principals: [ci]\n    resource: topic\n    name: a\n    actions: [describe]\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			writeKeys(t, path, content)
+			_, err := LoadPolicy(path)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package codec
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"unicode/utf8"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"kafka-admin-api/internal/model"
+	"kafka-admin-api/internal/schemaregistry"
+)
+
+// Formats for rendering record keys, values and header values.
+const (
+	UTF8   = "utf8"
+	Base64 = "base64"
+	Hex    = "hex"
+	JSON   = "json"
+)
+
+// Valid reports whether format is a supported message format.
+func Valid(format string) bool {
+	switch format {
+	case UTF8, Base64, Hex, JSON:
+		return true
+	}
+	return false
+}
+
+// Message converts a consumed record into its API representation.
+//
+// With JSON the value is embedded as-is when it is valid JSON; otherwise,
+// and for the key and headers, it is rendered like UTF8. UTF8 falls back to
+// Base64 for the whole message when any field is not valid UTF-8, so
+// binary payloads are never mangled. The format actually used is reported
+// per field: Message.Encoding for the value, KeyEncoding and
+// HeadersEncoding for the rest.
+func Message(msg *kafka.Message, format string) model.Message {
+	m := model.Message{
+		Topic:     *msg.TopicPartition.Topic,
+		Partition: msg.TopicPartition.Partition,
+		Offset:    int64(msg.TopicPartition.Offset),
+		Timestamp: msg.Timestamp.UnixMilli(),
+	}
+
+	encoding := format
+	if encoding == "" {
+		encoding = UTF8
+	}
+	textEncoding := encoding
+	if encoding == UTF8 || encoding == JSON {
+		textEncoding = UTF8
+		if !validUTF8(msg) {
+			textEncoding = Base64
+		}
+	}
+
+	switch {
+	case msg.Value == nil:
+		// Tombstones are rendered as null.
+	case encoding == JSON && json.Valid(msg.Value):
+		m.Value = json.RawMessage(msg.Value)
+	default:
+		m.Value = quote(encode(msg.Value, textEncoding))
+		encoding = textEncoding
+	}
+
+	if msg.Key != nil {
+		m.Key = encode(msg.Key, textEncoding)
+		m.KeyEncoding = textEncoding
+	}
+
+	headers := make(map[string]string)
+	for _, h := range msg.Headers {
+		headers[h.Key] = encode(h.Value, textEncoding)
+	}
+	m.Headers = headers
+	if len(headers) > 0 {
+		m.HeadersEncoding = textEncoding
+	}
+	m.Encoding = encoding
+
+	return m
+}
+
+// Renderer converts consumed records like Message and, when Registry is
+// set and Format is UTF8 or JSON, decodes values framed in the Schema
+// Registry wire format to JSON. Base64 and Hex always render the raw bytes.
+// Values the registry cannot decode are rendered in Format with the
+// failure reported in Message.SchemaError.
+type Renderer struct {
+	Format   string
+	Registry *schemaregistry.Client
+}
+
+func (r Renderer) Message(ctx context.Context, msg *kafka.Message) model.Message {
+	m := Message(msg, r.Format)
+	if r.Registry == nil || (r.Format != UTF8 && r.Format != JSON) || !schemaregistry.IsFramed(msg.Value) {
+		return m
+	}
+
+	decoded, err := r.Registry.Decode(ctx, m.Topic, msg.Value)
+	if err != nil {
+		m.SchemaError = err.Error()
+		return m
+	}
+
+	m.Value = decoded.Value
+	m.Encoding = JSON
+	m.SchemaID = decoded.SchemaID
+	m.Subject = decoded.Subject
+	return m
+}
+
+func validUTF8(msg *kafka.Message) bool {
+	if !utf8.Valid(msg.Key) {
+		return false
+	}
+	if !utf8.Valid(msg.Value) {
+		return false
+	}
+	for _, h := range msg.Headers {
+		if !utf8.Valid(h.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func encode(b []byte, format string) string {
+	switch format {
+	case Base64:
+		return base64.StdEncoding.EncodeToString(b)
+	case Hex:
+		return hex.EncodeToString(b)
+	default:
+		return string(b)
+	}
+}
+
+func quote(s string) json.RawMessage {
+	b, _ := json.Marshal(s)
+	return b
+}
@@ -0,0 +1,143 @@
+package codec
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+
+	"kafka-admin-api/internal/schemaregistry"
+)
+
+func newRecord(key, value []byte, headers ...kafka.Header) *kafka.Message {
+	topic := "orders"
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: 1, Offset: 42},
+		Key:            key,
+		Value:          value,
+		Timestamp:      time.UnixMilli(1700000000000),
+		Headers:        headers,
+	}
+}
+
+func TestMessageUTF8(t *testing.T) {
+	m := Message(newRecord([]byte("order-1"), []byte("créé"), kafka.Header{Key: "source", Value: []byte("web")}), UTF8)
+
+	assert.Equal(t, "orders", m.Topic)
+	assert.Equal(t, int64(42), m.Offset)
+	assert.Equal(t, "order-1", m.Key)
+	assert.JSONEq(t, `"créé"`, string(m.Value))
+	assert.Equal(t, "web", m.Headers["source"])
+	assert.Equal(t, UTF8, m.Encoding)
+}
+
+func TestMessageBinaryFallsBackToBase64(t *testing.T) {
+	m := Message(newRecord([]byte("k"), []byte{0x00, 0xff, 0xfe}), UTF8)
+
+	assert.Equal(t, Base64, m.Encoding)
+	assert.Equal(t, "aw==", m.Key)
+	assert.JSONEq(t, `"AP/+"`, string(m.Value))
+}
+
+func TestMessageHex(t *testing.T) {
+	m := Message(newRecord(nil, []byte("hi")), Hex)
+
+	assert.Equal(t, Hex, m.Encoding)
+	assert.Empty(t, m.Key)
+	assert.JSONEq(t, `"6869"`, string(m.Value))
+}
+
+func TestMessageJSON(t *testing.T) {
+	m := Message(newRecord([]byte("k"), []byte(`{"id": 1, "status": "created"}`)), JSON)
+
+	assert.Equal(t, JSON, m.Encoding)
+	assert.JSONEq(t, `{"id": 1, "status": "created"}`, string(m.Value))
+
+	data, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"value":{"id":1,"status":"created"}`)
+
+	// Values that are not JSON are rendered as strings instead.
+	m = Message(newRecord(nil, []byte("plain text")), JSON)
+	assert.Equal(t, UTF8, m.Encoding)
+	assert.JSONEq(t, `"plain text"`, string(m.Value))
+}
+
+func TestMessageJSONWithBinaryKey(t *testing.T) {
+	m := Message(newRecord([]byte{0xff, 0x01}, []byte(`{"id": 1}`), kafka.Header{Key: "trace", Value: []byte("abc")}), JSON)
+
+	assert.Equal(t, JSON, m.Encoding)
+	assert.JSONEq(t, `{"id": 1}`, string(m.Value))
+	assert.Equal(t, Base64, m.KeyEncoding)
+	assert.Equal(t, "/wE=", m.Key)
+	assert.Equal(t, Base64, m.HeadersEncoding)
+	assert.Equal(t, "YWJj", m.Headers["trace"])
+}
+
+func TestMessageTombstone(t *testing.T) {
+	m := Message(newRecord([]byte("k"), nil), UTF8)
+
+	data, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"value":null`)
+}
+
+func TestRendererDecodesSchemaFramedValues(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/ids/7":
+			_, _ = w.Write([]byte(`{"schema": "{}", "schemaType": "JSON"}`))
+		case "/schemas/ids/7/versions":
+			_, _ = w.Write([]byte(`[{"subject": "orders-value", "version": 1}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	r := Renderer{
+		Format:   UTF8,
+		Registry: schemaregistry.NewClient(schemaregistry.Config{URL: registry.URL}),
+	}
+
+	framed := append([]byte{0, 0, 0, 0, 7}, []byte(`{"id": 1}`)...)
+	m := r.Message(context.Background(), newRecord([]byte("k"), framed))
+	assert.Equal(t, JSON, m.Encoding)
+	assert.Equal(t, 7, m.SchemaID)
+	assert.Equal(t, "orders-value", m.Subject)
+	assert.JSONEq(t, `{"id": 1}`, string(m.Value))
+
+	// Unknown schema IDs fall back to the requested format.
+	unknown := []byte{0, 0, 0, 0, 8, 0xff}
+	m = r.Message(context.Background(), newRecord(nil, unknown))
+	assert.Equal(t, Base64, m.Encoding)
+	assert.NotEmpty(t, m.SchemaError)
+}
+
+func TestRendererKeepsRawFormats(t *testing.T) {
+	requests := 0
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer registry.Close()
+
+	framed := append([]byte{0, 0, 0, 0, 7}, []byte(`{"id": 1}`)...)
+	for _, format := range []string{Base64, Hex} {
+		r := Renderer{
+			Format:   format,
+			Registry: schemaregistry.NewClient(schemaregistry.Config{URL: registry.URL}),
+		}
+		m := r.Message(context.Background(), newRecord(nil, framed))
+		assert.Equal(t, format, m.Encoding)
+		assert.Equal(t, Message(newRecord(nil, framed), format).Value, m.Value)
+		assert.Zero(t, m.SchemaID)
+		assert.Empty(t, m.SchemaError)
+	}
+	assert.Zero(t, requests)
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"errors"
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -10,6 +13,42 @@ type Config struct {
 	SASLUsername     string `envconfig:"KAFKA_SASL_USERNAME"`
 	SASLPassword     string `envconfig:"KAFKA_SASL_PASSWORD"`
 	CALocation       string `envconfig:"KAFKA_CA_LOCATION"`
+
+	APIKeysFile           string        `envconfig:"API_KEYS_FILE"`
+	APIKeysReloadInterval time.Duration `envconfig:"API_KEYS_RELOAD_INTERVAL" default:"10s"`
+
+	JWTIssuer       string `envconfig:"JWT_ISSUER"`
+	JWTAudience     string `envconfig:"JWT_AUDIENCE"`
+	JWTJWKSURL      string `envconfig:"JWT_JWKS_URL"`
+	JWTJWKSFile     string `envconfig:"JWT_JWKS_FILE"`
+	JWTSubjectClaim string `envconfig:"JWT_SUBJECT_CLAIM" default:"sub"`
+	JWTGroupsClaim  string `envconfig:"JWT_GROUPS_CLAIM" default:"groups"`
+	JWTGroupScopes  string `envconfig:"JWT_GROUP_SCOPES"`
+
+	PolicyFile string `envconfig:"AUTHZ_POLICY_FILE"`
+
+	TopicPolicyFile string `envconfig:"TOPIC_POLICY_FILE"`
+
+	AuditLogFile    string `envconfig:"AUDIT_LOG_FILE"`
+	AuditTopic      string `envconfig:"AUDIT_TOPIC"`
+	AuditBufferSize int    `envconfig:"AUDIT_BUFFER_SIZE" default:"1000"`
+
+	ExpectedBrokers  int           `envconfig:"KAFKA_EXPECTED_BROKERS"`
+	ReadinessTimeout time.Duration `envconfig:"READINESS_TIMEOUT" default:"5s"`
+
+	ProducerAcks        string `envconfig:"KAFKA_PRODUCER_ACKS" default:"all"`
+	ProducerIdempotence bool   `envconfig:"KAFKA_PRODUCER_IDEMPOTENCE" default:"true"`
+
+	SchemaRegistryURL      string `envconfig:"SCHEMA_REGISTRY_URL"`
+	SchemaRegistryUsername string `envconfig:"SCHEMA_REGISTRY_USERNAME"`
+	SchemaRegistryPassword string `envconfig:"SCHEMA_REGISTRY_PASSWORD"`
+
+	LagExporterEnabled     bool          `envconfig:"LAG_EXPORTER_ENABLED" default:"false"`
+	LagExporterInterval    time.Duration `envconfig:"LAG_EXPORTER_INTERVAL" default:"30s"`
+	LagExporterInclude     string        `envconfig:"LAG_EXPORTER_GROUP_INCLUDE"`
+	LagExporterExclude     string        `envconfig:"LAG_EXPORTER_GROUP_EXCLUDE"`
+	LagExporterConcurrency int           `envconfig:"LAG_EXPORTER_CONCURRENCY" default:"4"`
+	LagHealthWindow        int           `envconfig:"LAG_HEALTH_WINDOW" default:"10"`
 }
 
 func Load() (*Config, error) {
@@ -17,5 +56,17 @@ func Load() (*Config, error) {
 	if err := envconfig.Process("", &cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
 	return &cfg, nil
 }
+
+// validate rejects combinations librdkafka would only refuse when the
+// producer is created.
+func (c *Config) validate() error {
+	if c.ProducerIdempotence && c.ProducerAcks != "all" && c.ProducerAcks != "-1" {
+		return errors.New("KAFKA_PRODUCER_ACKS must be all when KAFKA_PRODUCER_IDEMPOTENCE is enabled")
+	}
+	return nil
+}
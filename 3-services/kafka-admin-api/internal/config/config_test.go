@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProducerAcks(t *testing.T) {
+	tests := []struct {
+		acks        string
+		idempotence bool
+		valid       bool
+	}{
+		{"all", true, true},
+		{"-1", true, true},
+		{"1", true, false},
+		{"0", true, false},
+		{"1", false, true},
+	}
+
+	for _, tt := range tests {
+		cfg := Config{ProducerAcks: tt.acks, ProducerIdempotence: tt.idempotence}
+		err := cfg.validate()
+		if tt.valid {
+			assert.NoError(t, err, tt.acks)
+		} else {
+			assert.Error(t, err, tt.acks)
+		}
+	}
+}
+
+func TestLoadLagExporterOffByDefault(t *testing.T) {
+	t.Setenv("KAFKA_BOOTSTRAP_SERVERS", "localhost:9092")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.LagExporterEnabled)
+}
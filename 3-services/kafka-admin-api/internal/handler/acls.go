@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"kafka-admin-api/internal/auth"
+	"kafka-admin-api/internal/model"
+)
+
+func (h *Handler) listACLs(c *fiber.Ctx) error {
+	var filter model.ACLFilter
+	if err := c.QueryParser(&filter); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	acls, err := h.client.ListACLs(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("list acls failed", "error", err)
+		return err
+	}
+	return c.JSON(acls)
+}
+
+func (h *Handler) createACLs(c *fiber.Ctx) error {
+	var req model.CreateACLsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return h.writeACLs(c, req.ACLs)
+}
+
+// grantACLs expands a producer or consumer grant into the ACLs the role
+// needs, like the --producer and --consumer options of kafka-acls.
+func (h *Handler) grantACLs(c *fiber.Ctx) error {
+	var req model.GrantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return h.writeACLs(c, roleACLs(req))
+}
+
+func (h *Handler) writeACLs(c *fiber.Ctx, acls []model.ACL) error {
+	err := h.client.CreateACLs(c.Context(), acls)
+	for _, acl := range acls {
+		h.recordAudit(c, auth.ResourceACL, acl.ResourceName, auth.ActionCreate, nil, acl, err)
+	}
+	if err != nil {
+		h.logger.Error("create acls failed", "error", err)
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"acls": acls})
+}
+
+// deleteACLs deletes the ACLs matching the query filter. A resource name or
+// principal is required so an empty filter cannot wipe every ACL.
+func (h *Handler) deleteACLs(c *fiber.Ctx) error {
+	var filter model.ACLFilter
+	if err := c.QueryParser(&filter); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+	auditResource(c, filter.ResourceName)
+
+	if filter.ResourceName == "" && filter.Principal == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "resource_name or principal query parameter required")
+	}
+
+	deleted, err := h.client.DeleteACLs(c.Context(), filter)
+	if err != nil {
+		h.recordAudit(c, auth.ResourceACL, filter.ResourceName, auth.ActionDelete, filter, nil, err)
+		h.logger.Error("delete acls failed", "error", err)
+		return err
+	}
+	for _, acl := range deleted {
+		h.recordAudit(c, auth.ResourceACL, acl.ResourceName, auth.ActionDelete, acl, nil, nil)
+	}
+	return c.JSON(fiber.Map{"deleted": deleted})
+}
+
+// roleACLs returns WRITE, DESCRIBE and CREATE on the topic for producers;
+// READ and DESCRIBE on the topic plus READ on the group for consumers. The
+// pattern type applies to the topic and the group alike.
+func roleACLs(req model.GrantRequest) []model.ACL {
+	acl := func(resourceType, name, operation string) model.ACL {
+		return model.ACL{
+			ResourceType: resourceType,
+			ResourceName: name,
+			PatternType:  req.PatternType,
+			Principal:    req.Principal,
+			Host:         req.Host,
+			Operation:    operation,
+			Permission:   "ALLOW",
+		}
+	}
+
+	if req.Role == model.RoleProducer {
+		return []model.ACL{
+			acl("TOPIC", req.Topic, "WRITE"),
+			acl("TOPIC", req.Topic, "DESCRIBE"),
+			acl("TOPIC", req.Topic, "CREATE"),
+		}
+	}
+	return []model.ACL{
+		acl("TOPIC", req.Topic, "READ"),
+		acl("TOPIC", req.Topic, "DESCRIBE"),
+		acl("GROUP", req.Group, "READ"),
+	}
+}
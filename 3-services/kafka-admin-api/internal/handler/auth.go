@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"kafka-admin-api/internal/auth"
+)
+
+const (
+	apiKeyHeader = "X-API-Key"
+	principalKey = "principal"
+)
+
+// WithAPIKeys requires an API key with the right scope on every endpoint
+// except the health probes and /metrics.
+func WithAPIKeys(keys *auth.KeyStore) Option {
+	return func(h *Handler) {
+		h.keys = keys
+	}
+}
+
+// WithJWT accepts OIDC bearer tokens in addition to, or instead of, API keys.
+func WithJWT(verifier *auth.JWTVerifier) Option {
+	return func(h *Handler) {
+		h.jwt = verifier
+	}
+}
+
+// WithPolicy restricts topics and consumer groups to what the policy grants
+// the authenticated principal. It has no effect while authentication is off.
+func WithPolicy(policy *auth.Policy) Option {
+	return func(h *Handler) {
+		h.policy = policy
+	}
+}
+
+func (h *Handler) authEnabled() bool {
+	return h.keys != nil || h.jwt != nil
+}
+
+// publicPath reports whether path is served without authentication, so
+// probes and scrapers do not need credentials.
+func publicPath(path string) bool {
+	return path == "/health" || strings.HasPrefix(path, "/health/") || path == "/metrics"
+}
+
+// authenticate resolves the caller of the request and stores it as the
+// request principal. Authorization happens per route in require.
+func (h *Handler) authenticate(c *fiber.Ctx) error {
+	if !h.authEnabled() || publicPath(c.Path()) {
+		return c.Next()
+	}
+
+	principal, err := h.resolvePrincipal(c)
+	if err != nil {
+		return err
+	}
+	c.Locals(principalKey, principal)
+	return c.Next()
+}
+
+// resolvePrincipal authenticates a bearer token when JWT is enabled and an
+// API key otherwise.
+func (h *Handler) resolvePrincipal(c *fiber.Ctx) (*auth.Principal, error) {
+	if token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok && h.jwt != nil {
+		principal, err := h.jwt.Verify(c.Context(), strings.TrimSpace(token))
+		if err != nil {
+			h.logger.Warn("bearer token rejected", "error", err)
+			return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid bearer token")
+		}
+		return principal, nil
+	}
+
+	key := c.Get(apiKeyHeader)
+	if key == "" || h.keys == nil {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "missing credentials")
+	}
+	principal, ok := h.keys.Authenticate(key)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "invalid API key")
+	}
+	return principal, nil
+}
+
+// require rejects requests whose principal lacks scope.
+func (h *Handler) require(scope auth.Scope) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !h.authEnabled() {
+			return c.Next()
+		}
+		principal := requestPrincipal(c)
+		if principal == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+		}
+		if !principal.HasScope(scope) {
+			return fiber.NewError(fiber.StatusForbidden,
+				fmt.Sprintf("%s is missing scope %s", principal.ID(), scope))
+		}
+		return c.Next()
+	}
+}
+
+// requireAdmin is require for the routes that manage credentials, ACLs,
+// quotas and broker configs. Unlike require it refuses every request while
+// authentication is off, so an open port cannot be used to take over the
+// cluster.
+func (h *Handler) requireAdmin(scope auth.Scope) fiber.Handler {
+	require := h.require(scope)
+	return func(c *fiber.Ctx) error {
+		if !h.authEnabled() {
+			return fiber.NewError(fiber.StatusForbidden,
+				fmt.Sprintf("%s requires authentication, configure API keys or JWT", scope))
+		}
+		return require(c)
+	}
+}
+
+func requestPrincipal(c *fiber.Ctx) *auth.Principal {
+	principal, _ := c.Locals(principalKey).(*auth.Principal)
+	return principal
+}
+
+// authorize returns a 403 error unless the policy lets the request principal
+// perform action on the named resource.
+func (h *Handler) authorize(c *fiber.Ctx, resource auth.ResourceType, name string, action auth.Action) error {
+	if h.policy == nil || !h.authEnabled() {
+		return nil
+	}
+	principal := requestPrincipal(c)
+	if !h.policy.Allowed(principal, resource, name, action) {
+		caller := "anonymous"
+		if principal != nil {
+			caller = principal.ID()
+		}
+		return fiber.NewError(fiber.StatusForbidden,
+			fmt.Sprintf("%s is not allowed to %s %s %s", caller, action, resource, name))
+	}
+	return nil
+}
+
+// allow authorizes action on the resource named by the route parameter param.
+func (h *Handler) allow(resource auth.ResourceType, param string, action auth.Action) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := h.authorize(c, resource, c.Params(param), action); err != nil {
+			return err
+		}
+		return c.Next()
+	}
+}
+
+// visible reports whether the caller may describe the resource. Listings
+// leave out anything that is not visible.
+func (h *Handler) visible(c *fiber.Ctx, resource auth.ResourceType, name string) bool {
+	return h.authorize(c, resource, name, auth.ActionDescribe) == nil
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"kafka-admin-api/internal/auth"
+	"kafka-admin-api/internal/model"
+)
+
+// sensitiveValue stands in for sensitive config values in audit events.
+const sensitiveValue = "[sensitive]"
+
+// getTopicConfig describes a single topic config, including where its
+// effective value comes from.
+func (h *Handler) getTopicConfig(c *fiber.Ctx) error {
+	topicName := c.Params("topicName")
+	key := c.Params("key")
+
+	entries, err := h.client.DescribeTopicConfigs(c.Context(), topicName)
+	if err != nil {
+		h.logger.Error("describe topic configs failed", "topic", topicName, "error", err)
+		return err
+	}
+	for _, e := range entries {
+		if e.Name == key {
+			return c.JSON(e)
+		}
+	}
+	return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("topic %s has no config %s", topicName, key))
+}
+
+func (h *Handler) getBrokerConfigs(c *fiber.Ctx) error {
+	broker, err := h.brokerParam(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.client.DescribeBrokerConfigs(c.Context(), broker)
+	if err != nil {
+		h.logger.Error("describe broker configs failed", "broker", broker, "error", err)
+		return err
+	}
+	return c.JSON(entries)
+}
+
+// getClusterConfigs returns the cluster-wide dynamic broker defaults. Only
+// keys set at that level are listed; GET /brokers/:id/configs shows the
+// effective value and source of every key.
+func (h *Handler) getClusterConfigs(c *fiber.Ctx) error {
+	entries, err := h.client.DescribeBrokerConfigs(c.Context(), "")
+	if err != nil {
+		h.logger.Error("describe cluster configs failed", "error", err)
+		return err
+	}
+	return c.JSON(entries)
+}
+
+func (h *Handler) updateBrokerConfigs(c *fiber.Ctx) error {
+	broker, err := h.brokerParam(c)
+	if err != nil {
+		return err
+	}
+	req, err := h.parseConfigUpdate(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.client.DescribeBrokerConfigs(c.Context(), broker)
+	if err != nil {
+		h.logger.Error("describe broker configs failed", "broker", broker, "error", err)
+		return err
+	}
+	if err := checkConfigUpdate(entries, req.Configs); err != nil {
+		return err
+	}
+
+	err = h.client.AlterBrokerConfigs(c.Context(), broker, req.Configs)
+	h.recordAudit(c, auth.ResourceBroker, broker, auth.ActionAlter,
+		fiber.Map{"configs": currentValues(entries, req.Configs)}, fiber.Map{"configs": maskSensitive(entries, req.Configs)}, err)
+	if err != nil {
+		h.logger.Error("alter broker configs failed", "broker", broker, "error", err)
+		return err
+	}
+	return c.JSON(fiber.Map{"message": "broker configs updated"})
+}
+
+// updateClusterConfigs sets cluster-wide dynamic defaults. Keys are checked
+// against the metadata of a live broker, since the cluster level only
+// describes keys that are already set there.
+func (h *Handler) updateClusterConfigs(c *fiber.Ctx) error {
+	req, err := h.parseConfigUpdate(c)
+	if err != nil {
+		return err
+	}
+
+	brokers, err := h.client.ListBrokers(c.Context())
+	if err != nil {
+		h.logger.Error("list brokers failed", "error", err)
+		return err
+	}
+	if len(brokers) == 0 {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "no brokers available")
+	}
+	sort.Slice(brokers, func(i, j int) bool { return brokers[i].ID < brokers[j].ID })
+	reference := strconv.Itoa(int(brokers[0].ID))
+
+	entries, err := h.client.DescribeBrokerConfigs(c.Context(), reference)
+	if err != nil {
+		h.logger.Error("describe broker configs failed", "broker", reference, "error", err)
+		return err
+	}
+	if err := checkConfigUpdate(entries, req.Configs); err != nil {
+		return err
+	}
+
+	current, err := h.client.DescribeBrokerConfigs(c.Context(), "")
+	if err != nil {
+		h.logger.Error("describe cluster configs failed", "error", err)
+		return err
+	}
+
+	err = h.client.AlterBrokerConfigs(c.Context(), "", req.Configs)
+	h.recordAudit(c, auth.ResourceCluster, "", auth.ActionAlter,
+		fiber.Map{"configs": currentValues(current, req.Configs)}, fiber.Map{"configs": maskSensitive(entries, req.Configs)}, err)
+	if err != nil {
+		h.logger.Error("alter cluster configs failed", "error", err)
+		return err
+	}
+	return c.JSON(fiber.Map{"message": "cluster configs updated"})
+}
+
+// brokerParam returns the :id parameter after checking the broker exists,
+// as describing an unknown broker only fails after a timeout.
+func (h *Handler) brokerParam(c *fiber.Ctx) (string, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusBadRequest, "broker id must be an integer")
+	}
+	if err := h.requireBroker(c.Context(), int32(id)); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(id), nil
+}
+
+func (h *Handler) requireBroker(ctx context.Context, id int32) error {
+	brokers, err := h.client.ListBrokers(ctx)
+	if err != nil {
+		h.logger.Error("list brokers failed", "error", err)
+		return err
+	}
+	for _, b := range brokers {
+		if b.ID == id {
+			return nil
+		}
+	}
+	return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("broker %d not found", id))
+}
+
+func (h *Handler) parseConfigUpdate(c *fiber.Ctx) (*model.UpdateConfigsRequest, error) {
+	var req model.UpdateConfigsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return &req, nil
+}
+
+// checkConfigUpdate rejects keys the broker does not know and keys that
+// cannot be changed without a restart, which Kafka reports as read-only.
+func checkConfigUpdate(entries []model.ConfigEntry, configs map[string]string) error {
+	described := make(map[string]model.ConfigEntry, len(entries))
+	for _, e := range entries {
+		described[e.Name] = e
+	}
+
+	var problems []string
+	for _, k := range sortedKeys(configs) {
+		entry, ok := described[k]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("unknown config %s", k))
+		case entry.ReadOnly:
+			problems = append(problems, fmt.Sprintf("%s is not dynamically updatable", k))
+		}
+	}
+	if len(problems) > 0 {
+		return fiber.NewError(fiber.StatusBadRequest, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// currentValues returns the described values of the keys being changed,
+// masking sensitive ones. Keys not described are left out.
+func currentValues(entries []model.ConfigEntry, configs map[string]string) map[string]string {
+	values := make(map[string]string, len(configs))
+	for _, e := range entries {
+		if _, ok := configs[e.Name]; !ok {
+			continue
+		}
+		switch {
+		case e.Sensitive:
+			values[e.Name] = sensitiveValue
+		case e.Value != nil:
+			values[e.Name] = *e.Value
+		}
+	}
+	return values
+}
+
+// maskSensitive returns configs with the values of sensitive keys masked.
+func maskSensitive(entries []model.ConfigEntry, configs map[string]string) map[string]string {
+	masked := make(map[string]string, len(configs))
+	for k, v := range configs {
+		masked[k] = v
+	}
+	for _, e := range entries {
+		if _, ok := masked[e.Name]; ok && e.Sensitive {
+			masked[e.Name] = sensitiveValue
+		}
+	}
+	return masked
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/gofiber/fiber/v2"
+
+	kafkaclient "kafka-admin-api/internal/kafka"
+	"kafka-admin-api/internal/topicpolicy"
+)
+
+// codeStatus maps Kafka error codes to HTTP statuses. Unlisted codes are
+// reported as 500.
+var codeStatus = map[kafka.ErrorCode]int{
+	kafka.ErrTopicAlreadyExists:         fiber.StatusConflict,
+	kafka.ErrNonEmptyGroup:              fiber.StatusConflict,
+	kafka.ErrGroupSubscribedToTopic:     fiber.StatusConflict,
+	kafka.ErrUnknownTopicOrPart:         fiber.StatusNotFound,
+	kafka.ErrUnknownTopic:               fiber.StatusNotFound,
+	kafka.ErrGroupIDNotFound:            fiber.StatusNotFound,
+	kafka.ErrResourceNotFound:           fiber.StatusNotFound,
+	kafka.ErrInvalidReplicationFactor:   fiber.StatusBadRequest,
+	kafka.ErrInvalidReplicaAssignment:   fiber.StatusBadRequest,
+	kafka.ErrInvalidPartitions:          fiber.StatusBadRequest,
+	kafka.ErrInvalidConfig:              fiber.StatusBadRequest,
+	kafka.ErrInvalidRequest:             fiber.StatusBadRequest,
+	kafka.ErrInvalidArg:                 fiber.StatusBadRequest,
+	kafka.ErrPolicyViolation:            fiber.StatusBadRequest,
+	kafka.ErrUnsupportedSaslMechanism:   fiber.StatusBadRequest,
+	kafka.ErrUnacceptableCredential:     fiber.StatusBadRequest,
+	kafka.ErrTopicAuthorizationFailed:   fiber.StatusForbidden,
+	kafka.ErrGroupAuthorizationFailed:   fiber.StatusForbidden,
+	kafka.ErrClusterAuthorizationFailed: fiber.StatusForbidden,
+	kafka.ErrTopicDeletionDisabled:      fiber.StatusForbidden,
+	kafka.ErrUnsupportedVersion:         fiber.StatusNotImplemented,
+	kafka.ErrSecurityDisabled:           fiber.StatusNotImplemented,
+	kafka.ErrTransport:                  fiber.StatusServiceUnavailable,
+	kafka.ErrAllBrokersDown:             fiber.StatusServiceUnavailable,
+}
+
+// ErrorHandler renders every error returned by a route as
+// {"error", "code", "request_id"}. Kafka errors are mapped by code, fiber
+// errors keep their status and anything else is a 500. Topic policy errors
+// add the list of violations.
+func (h *Handler) ErrorHandler(c *fiber.Ctx, err error) error {
+	status, code := errorStatus(err)
+	var (
+		details fiber.Map
+		perr    *topicpolicy.Error
+	)
+	if errors.As(err, &perr) {
+		details = fiber.Map{"violations": perr.Violations}
+	}
+	return writeError(c, status, code, err.Error(), details)
+}
+
+// handleErrors runs ErrorHandler inside the middleware chain, so the
+// logging and metrics middlewares observe the mapped status.
+func (h *Handler) handleErrors(c *fiber.Ctx) error {
+	if err := c.Next(); err != nil {
+		return h.ErrorHandler(c, err)
+	}
+	return nil
+}
+
+func errorStatus(err error) (int, string) {
+	var (
+		kerr *kafkaclient.Error
+		perr *topicpolicy.Error
+		ferr *fiber.Error
+	)
+	switch {
+	case errors.As(err, &kerr):
+		if kerr.Timeout() {
+			return fiber.StatusGatewayTimeout, kerr.Name()
+		}
+		if status, ok := codeStatus[kerr.Code()]; ok {
+			return status, kerr.Name()
+		}
+		return fiber.StatusInternalServerError, kerr.Name()
+	case errors.As(err, &perr):
+		return fiber.StatusUnprocessableEntity, "TOPIC_POLICY_VIOLATION"
+	case errors.Is(err, context.DeadlineExceeded):
+		return fiber.StatusGatewayTimeout, "TIMED_OUT"
+	case errors.Is(err, errors.ErrUnsupported):
+		return fiber.StatusNotImplemented, ""
+	case errors.As(err, &ferr):
+		return ferr.Code, ""
+	}
+	return fiber.StatusInternalServerError, ""
+}
+
+// writeError writes the common error body. details are merged into it for
+// responses that carry more context, e.g. the groups blocking a delete.
+func writeError(c *fiber.Ctx, status int, code, message string, details fiber.Map) error {
+	body := fiber.Map{"error": message}
+	if code != "" {
+		body["code"] = code
+	}
+	if id := c.GetRespHeader(fiber.HeaderXRequestID); id != "" {
+		body["request_id"] = id
+	}
+	for k, v := range details {
+		body[k] = v
+	}
+	return c.Status(status).JSON(body)
+}
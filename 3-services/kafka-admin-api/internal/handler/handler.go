@@ -3,29 +3,62 @@ package handler
 import (
 	"bufio"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 
+	"kafka-admin-api/internal/audit"
+	"kafka-admin-api/internal/auth"
+	"kafka-admin-api/internal/codec"
+	"kafka-admin-api/internal/lag"
+	"kafka-admin-api/internal/metrics"
 	"kafka-admin-api/internal/model"
+	"kafka-admin-api/internal/schemaregistry"
+	"kafka-admin-api/internal/topicpolicy"
 )
 
 type KafkaClient interface {
 	ListBrokers(ctx context.Context) ([]model.Broker, error)
+	DescribeCluster(ctx context.Context) (*model.Cluster, error)
 	ListTopics(ctx context.Context) ([]model.Topic, error)
 	GetTopic(ctx context.Context, name string) (*model.TopicDetail, error)
 	CreateTopic(ctx context.Context, req model.CreateTopicRequest) error
 	UpdateTopicConfig(ctx context.Context, name string, configs map[string]string) error
+	CreatePartitions(ctx context.Context, name string, count int32, assignment [][]int32) error
+	DeleteTopic(ctx context.Context, name string) error
 	ListConsumerGroups(ctx context.Context) ([]model.ConsumerGroup, error)
 	GetConsumerGroup(ctx context.Context, groupID string) (*model.ConsumerGroupDetail, error)
+	DescribeConsumerGroups(ctx context.Context, groupIDs []string) ([]model.ConsumerGroupDetail, error)
+	ResetConsumerGroupOffsets(ctx context.Context, groupID string, req model.ResetOffsetsRequest) ([]model.OffsetReset, error)
+	DeleteConsumerGroups(ctx context.Context, groupIDs []string) ([]model.ConsumerGroupResult, error)
+	DeleteConsumerGroupOffsets(ctx context.Context, groupID, topic string) (*model.ConsumerGroupResult, error)
+	Produce(ctx context.Context, topic string, messages []model.ProduceMessage) ([]model.ProduceResult, error)
+	DescribeScramUsers(ctx context.Context, users []string) ([]model.ScramUser, error)
+	UpsertScramCredential(ctx context.Context, user, mechanism string, iterations int, password []byte) error
+	DeleteScramCredentials(ctx context.Context, user string, mechanisms []string) error
+	DescribeTopicConfigs(ctx context.Context, topic string) ([]model.ConfigEntry, error)
+	DescribeBrokerConfigs(ctx context.Context, broker string) ([]model.ConfigEntry, error)
+	AlterBrokerConfigs(ctx context.Context, broker string, configs map[string]string) error
+	DescribeClientQuotas(ctx context.Context, filter model.QuotaEntity) ([]model.ClientQuota, error)
+	AlterClientQuotas(ctx context.Context, entity model.QuotaEntity, set map[string]float64, remove []string) error
+	ListACLs(ctx context.Context, filter model.ACLFilter) ([]model.ACL, error)
+	CreateACLs(ctx context.Context, acls []model.ACL) error
+	DeleteACLs(ctx context.Context, filter model.ACLFilter) ([]model.ACL, error)
+	BrowseMessages(ctx context.Context, topic string, req model.BrowseRequest, renderer codec.Renderer) (*model.BrowsePage, error)
 	CreateConsumer(groupID, autoOffset string) (*kafka.Consumer, error)
 	ConsumeMessages(ctx context.Context, topic, groupID, autoOffset string, maxMessages int, msgChan chan<- model.Message) error
 	Close()
@@ -35,16 +68,77 @@ type Handler struct {
 	client   KafkaClient
 	logger   *slog.Logger
 	validate *validator.Validate
+	registry *schemaregistry.Client
+	metrics  *metrics.Metrics
+	lag      *lag.Evaluator
+	keys     *auth.KeyStore
+	jwt      *auth.JWTVerifier
+	policy   *auth.Policy
+	audit    *audit.Log
+	topics   *topicpolicy.Policy
+
+	expectedBrokers  int
+	readinessTimeout time.Duration
+}
+
+// Option configures optional Handler dependencies.
+type Option func(*Handler)
+
+// WithSchemaRegistry decodes Schema Registry framed message values to JSON
+// in the consume endpoints.
+func WithSchemaRegistry(registry *schemaregistry.Client) Option {
+	return func(h *Handler) {
+		h.registry = registry
+	}
+}
+
+// WithMetrics records request metrics and serves them on GET /metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(h *Handler) {
+		h.metrics = m
+	}
+}
+
+// WithLagEvaluator serves lag health on GET /consumer-groups/:groupID/health
+// and adds the status to the consumer group listing.
+func WithLagEvaluator(evaluator *lag.Evaluator) Option {
+	return func(h *Handler) {
+		h.lag = evaluator
+	}
 }
 
-func New(client KafkaClient, logger *slog.Logger) *Handler {
-	return &Handler{
-		client:   client,
-		logger:   logger,
-		validate: validator.New(),
+// WithReadiness sets the broker count /health/ready expects and the time
+// budget of the readiness checks.
+func WithReadiness(expectedBrokers int, timeout time.Duration) Option {
+	return func(h *Handler) {
+		h.expectedBrokers = expectedBrokers
+		if timeout > 0 {
+			h.readinessTimeout = timeout
+		}
+	}
+}
+
+// WithTopicPolicy enforces naming, partition, replication and config rules
+// on topic creates and updates, answering violations with 422.
+func WithTopicPolicy(policy *topicpolicy.Policy) Option {
+	return func(h *Handler) {
+		h.topics = policy
 	}
 }
 
+func New(client KafkaClient, logger *slog.Logger, opts ...Option) *Handler {
+	h := &Handler{
+		client:           client,
+		logger:           logger,
+		validate:         validator.New(),
+		readinessTimeout: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
 func NewWithClient(client KafkaClient, logger *slog.Logger) *Handler {
 	return New(client, logger)
 }
@@ -52,38 +146,185 @@ func NewWithClient(client KafkaClient, logger *slog.Logger) *Handler {
 func (h *Handler) SetupRoutes(app *fiber.App) {
 	app.Use(recover.New())
 	app.Use(requestid.New())
+	if h.metrics != nil {
+		app.Use(h.metrics.Middleware)
+	}
 	app.Use(h.loggingMiddleware)
+	app.Use(h.handleErrors)
+	app.Use(h.authenticate)
+
+	if h.metrics != nil {
+		app.Get("/metrics", adaptor.HTTPHandler(h.metrics.Handler()))
+	}
+	app.Get("/health", h.live)
+	app.Get("/health/live", h.live)
+	app.Get("/health/ready", h.ready)
 
-	app.Get("/health", h.health)
-	app.Get("/brokers", h.listBrokers)
-	app.Get("/topics", h.listTopics)
-	app.Post("/topics", h.createTopic)
-	app.Get("/topics/:topicName", h.getTopic)
-	app.Put("/topics/:topicName", h.updateTopic)
-	app.Get("/consumer-groups", h.listConsumerGroups)
-	app.Get("/consumer-groups/:groupID", h.getConsumerGroup)
-	app.Get("/topics/:topicName/consume", h.consumeMessagesBatch)
-	app.Get("/topics/:topicName/messages", h.consumeMessagesSSE)
+	readMetadata := h.require(auth.ScopeReadMetadata)
+	manageTopics := h.require(auth.ScopeManageTopics)
+	manageGroups := h.require(auth.ScopeManageGroups)
+	readMessages := h.require(auth.ScopeReadMessages)
+	produce := h.require(auth.ScopeProduce)
+
+	topic := func(action auth.Action) fiber.Handler {
+		return h.allow(auth.ResourceTopic, "topicName", action)
+	}
+	group := func(action auth.Action) fiber.Handler {
+		return h.allow(auth.ResourceGroup, "groupID", action)
+	}
+
+	app.Get("/brokers", readMetadata, h.listBrokers)
+	app.Get("/brokers/:id/configs", readMetadata, h.getBrokerConfigs)
+	app.Put("/brokers/:id/configs", h.audited(auth.ResourceBroker, "id", auth.ActionAlter),
+		h.requireAdmin(auth.ScopeManageCluster), h.updateBrokerConfigs)
+	app.Get("/cluster/configs", readMetadata, h.getClusterConfigs)
+	app.Put("/cluster/configs", h.audited(auth.ResourceCluster, "", auth.ActionAlter),
+		h.requireAdmin(auth.ScopeManageCluster), h.updateClusterConfigs)
+	app.Get("/topics", readMetadata, h.listTopics)
+	app.Post("/topics", h.audited(auth.ResourceTopic, "", auth.ActionCreate), manageTopics, h.createTopic)
+	app.Post("/topics\\:plan", readMetadata, h.planTopics)
+	app.Post("/topics\\:apply", h.audited(auth.ResourceTopic, "", auth.ActionAlter), manageTopics, h.applyTopics)
+	app.Get("/topics/:topicName", readMetadata, topic(auth.ActionDescribe), h.getTopic)
+	app.Get("/topics/:topicName/configs/:key", readMetadata, topic(auth.ActionDescribe), h.getTopicConfig)
+	app.Put("/topics/:topicName", h.audited(auth.ResourceTopic, "topicName", auth.ActionAlter),
+		manageTopics, topic(auth.ActionAlter), h.updateTopic)
+	app.Delete("/topics/:topicName", h.audited(auth.ResourceTopic, "topicName", auth.ActionDelete),
+		manageTopics, topic(auth.ActionDelete), h.deleteTopic)
+	app.Post("/topics/:topicName/partitions", h.audited(auth.ResourceTopic, "topicName", auth.ActionAlter),
+		manageTopics, topic(auth.ActionAlter), h.createPartitions)
+	app.Get("/consumer-groups", readMetadata, h.listConsumerGroups)
+	app.Get("/consumer-groups/:groupID", readMetadata, group(auth.ActionDescribe), h.getConsumerGroup)
+	app.Get("/consumer-groups/:groupID/health", readMetadata, group(auth.ActionDescribe), h.getConsumerGroupHealth)
+	app.Delete("/consumer-groups/:groupID", h.audited(auth.ResourceGroup, "groupID", auth.ActionDelete),
+		manageGroups, group(auth.ActionDelete), h.deleteConsumerGroup)
+	app.Post("/consumer-groups/:groupID/offsets/reset", h.audited(auth.ResourceGroup, "groupID", auth.ActionResetOffsets),
+		manageGroups, group(auth.ActionResetOffsets), h.resetConsumerGroupOffsets)
+	app.Delete("/consumer-groups/:groupID/offsets", h.audited(auth.ResourceGroup, "groupID", auth.ActionResetOffsets),
+		manageGroups, group(auth.ActionResetOffsets), h.deleteConsumerGroupOffsets)
+	app.Get("/topics/:topicName/consume", readMessages, topic(auth.ActionConsume), h.consumeMessagesBatch)
+	app.Get("/topics/:topicName/browse", readMessages, topic(auth.ActionConsume), h.browseMessages)
+	app.Get("/topics/:topicName/messages", readMessages, topic(auth.ActionConsume), h.consumeMessagesSSE)
+	app.Post("/topics/:topicName/messages", produce, topic(auth.ActionProduce), h.produceMessages)
+	app.Get("/audit", h.require(auth.ScopeReadAudit), h.listAuditEvents)
+
+	manageACLs := h.requireAdmin(auth.ScopeManageACLs)
+	app.Get("/acls", manageACLs, h.listACLs)
+	app.Post("/acls", h.audited(auth.ResourceACL, "", auth.ActionCreate), manageACLs, h.createACLs)
+	app.Delete("/acls", h.audited(auth.ResourceACL, "", auth.ActionDelete), manageACLs, h.deleteACLs)
+	app.Post("/acls/grants", h.audited(auth.ResourceACL, "", auth.ActionCreate), manageACLs, h.grantACLs)
+
+	manageUsers := h.requireAdmin(auth.ScopeManageUsers)
+	app.Get("/scram-users", manageUsers, h.listScramUsers)
+	app.Get("/scram-users/:user", manageUsers, h.getScramUser)
+	app.Put("/scram-users/:user", h.audited(auth.ResourceUser, "user", auth.ActionAlter), manageUsers, h.upsertScramCredential)
+	app.Delete("/scram-users/:user", h.audited(auth.ResourceUser, "user", auth.ActionDelete), manageUsers, h.deleteScramCredentials)
+
+	manageQuotas := h.requireAdmin(auth.ScopeManageQuotas)
+	app.Get("/quotas", readMetadata, h.listQuotas)
+	app.Put("/quotas", h.audited(auth.ResourceQuota, "", auth.ActionAlter), manageQuotas, h.setQuotas)
+	app.Delete("/quotas", h.audited(auth.ResourceQuota, "", auth.ActionDelete), manageQuotas, h.deleteQuotas)
 }
 
+// loggingMiddleware logs each request once it completes, so the log line
+// carries the authenticated principal and the final status.
 func (h *Handler) loggingMiddleware(c *fiber.Ctx) error {
-	h.logger.Info("request",
+	err := c.Next()
+
+	attrs := []any{
 		"method", c.Method(),
 		"path", c.Path(),
+		"status", c.Response().StatusCode(),
 		"request_id", c.Locals("requestid"),
-	)
-	return c.Next()
+	}
+	if principal := requestPrincipal(c); principal != nil {
+		attrs = append(attrs, "principal", principal.ID())
+	}
+	h.logger.Info("request", attrs...)
+	return err
 }
 
-func (h *Handler) health(c *fiber.Ctx) error {
+// live reports that the process is up. It deliberately does not touch Kafka
+// so a cluster outage does not get the API restarted.
+func (h *Handler) live(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"status": "healthy"})
 }
 
+// ready checks that the cluster answers a metadata request, that the
+// expected number of brokers is registered and that the controller accepts
+// connections, all within readinessTimeout. Missing brokers degrade the
+// result without failing it.
+func (h *Handler) ready(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.Context(), h.readinessTimeout)
+	defer cancel()
+
+	readiness := model.Readiness{Status: "ready", ExpectedBrokers: h.expectedBrokers, ControllerID: -1}
+	fail := func(check model.ReadinessCheck) error {
+		readiness.Status = "not_ready"
+		readiness.Checks = append(readiness.Checks, check)
+		h.logger.Warn("readiness check failed", "check", check.Name, "detail", check.Detail)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(readiness)
+	}
+
+	start := time.Now()
+	cluster, err := h.client.DescribeCluster(ctx)
+	metadata := model.ReadinessCheck{Name: "metadata", Status: model.CheckOK, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		metadata.Status = model.CheckFailed
+		metadata.Detail = err.Error()
+		return fail(metadata)
+	}
+	readiness.Checks = append(readiness.Checks, metadata)
+	readiness.Brokers = len(cluster.Brokers)
+	readiness.ControllerID = cluster.ControllerID
+
+	brokers := model.ReadinessCheck{
+		Name:   "brokers",
+		Status: model.CheckOK,
+		Detail: fmt.Sprintf("%d brokers registered", len(cluster.Brokers)),
+	}
+	switch {
+	case len(cluster.Brokers) == 0:
+		brokers.Status = model.CheckFailed
+		return fail(brokers)
+	case h.expectedBrokers > 0 && len(cluster.Brokers) < h.expectedBrokers:
+		brokers.Status = model.CheckDegraded
+		brokers.Detail = fmt.Sprintf("%d of %d expected brokers registered", len(cluster.Brokers), h.expectedBrokers)
+	}
+	readiness.Checks = append(readiness.Checks, brokers)
+
+	controller := model.ReadinessCheck{Name: "controller", Status: model.CheckOK}
+	var address string
+	for _, b := range cluster.Brokers {
+		if b.ID == cluster.ControllerID {
+			address = net.JoinHostPort(b.Host, strconv.Itoa(int(b.Port)))
+		}
+	}
+	if address == "" {
+		controller.Status = model.CheckFailed
+		controller.Detail = "no active controller"
+		return fail(controller)
+	}
+
+	start = time.Now()
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", address)
+	controller.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		controller.Status = model.CheckFailed
+		controller.Detail = fmt.Sprintf("controller %d unreachable: %v", cluster.ControllerID, err)
+		return fail(controller)
+	}
+	conn.Close()
+	controller.Detail = fmt.Sprintf("controller %d reachable at %s", cluster.ControllerID, address)
+	readiness.Checks = append(readiness.Checks, controller)
+
+	return c.JSON(readiness)
+}
+
 func (h *Handler) listBrokers(c *fiber.Ctx) error {
 	brokers, err := h.client.ListBrokers(c.Context())
 	if err != nil {
 		h.logger.Error("list brokers failed", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(brokers)
 }
@@ -92,21 +333,40 @@ func (h *Handler) listTopics(c *fiber.Ctx) error {
 	topics, err := h.client.ListTopics(c.Context())
 	if err != nil {
 		h.logger.Error("list topics failed", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
+	}
+
+	visible := topics[:0]
+	for _, t := range topics {
+		if h.visible(c, auth.ResourceTopic, t.Name) {
+			visible = append(visible, t)
+		}
 	}
-	return c.JSON(topics)
+	return c.JSON(visible)
 }
 
 func (h *Handler) getTopic(c *fiber.Ctx) error {
 	topicName := c.Params("topicName")
 	if topicName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "topic name required"})
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
+	}
+
+	include := c.Query("include")
+	if include != "" && include != "all" {
+		return fiber.NewError(fiber.StatusBadRequest, "include must be all")
 	}
 
 	topic, err := h.client.GetTopic(c.Context(), topicName)
 	if err != nil {
 		h.logger.Error("get topic failed", "topic", topicName, "error", err)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		return err
+	}
+	if include == "all" {
+		topic.ConfigEntries, err = h.client.DescribeTopicConfigs(c.Context(), topicName)
+		if err != nil {
+			h.logger.Error("describe topic configs failed", "topic", topicName, "error", err)
+			return err
+		}
 	}
 	return c.JSON(topic)
 }
@@ -114,75 +374,546 @@ func (h *Handler) getTopic(c *fiber.Ctx) error {
 func (h *Handler) createTopic(c *fiber.Ctx) error {
 	var req model.CreateTopicRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
+	auditResource(c, req.Name)
 
 	if err := h.validate.Struct(req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	if err := h.client.CreateTopic(c.Context(), req); err != nil {
+	if err := h.authorize(c, auth.ResourceTopic, req.Name, auth.ActionCreate); err != nil {
+		return err
+	}
+
+	if err := h.checkCreatePolicy(c.Context(), req); err != nil {
+		return err
+	}
+
+	err := h.client.CreateTopic(c.Context(), req)
+	h.recordAudit(c, auth.ResourceTopic, req.Name, auth.ActionCreate, nil, req, err)
+	if err != nil {
 		h.logger.Error("create topic failed", "topic", req.Name, "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "topic created"})
 }
 
+// checkCreatePolicy validates req against the topic policy, looking up the
+// broker count only when the policy needs it.
+func (h *Handler) checkCreatePolicy(ctx context.Context, req model.CreateTopicRequest) error {
+	brokers := 0
+	if h.topics.RequiresBrokerCount() {
+		list, err := h.client.ListBrokers(ctx)
+		if err != nil {
+			h.logger.Error("list brokers failed", "error", err)
+			return err
+		}
+		brokers = len(list)
+	}
+	return h.topics.CheckCreate(req, brokers)
+}
+
 func (h *Handler) updateTopic(c *fiber.Ctx) error {
 	topicName := c.Params("topicName")
 	if topicName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "topic name required"})
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
 	}
 
 	var req model.UpdateTopicRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
 	if len(req.Configs) == 0 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "configs required"})
+		return fiber.NewError(fiber.StatusBadRequest, "configs required")
+	}
+
+	if err := h.topics.CheckConfigs(req.Configs); err != nil {
+		return err
 	}
 
-	if err := h.client.UpdateTopicConfig(c.Context(), topicName, req.Configs); err != nil {
+	// Only the keys being changed are audited, with their effective values
+	// before the change, defaults included, and as requested.
+	var before map[string]string
+	if h.audit != nil {
+		entries, err := h.client.DescribeTopicConfigs(c.Context(), topicName)
+		if err != nil {
+			h.logger.Error("describe topic configs failed", "topic", topicName, "error", err)
+			return err
+		}
+		before = currentValues(entries, req.Configs)
+	}
+
+	err := h.client.UpdateTopicConfig(c.Context(), topicName, req.Configs)
+	h.recordAudit(c, auth.ResourceTopic, topicName, auth.ActionAlter,
+		fiber.Map{"configs": before}, fiber.Map{"configs": req.Configs}, err)
+	if err != nil {
 		h.logger.Error("update topic failed", "topic", topicName, "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	return c.JSON(fiber.Map{"message": "topic config updated"})
 }
 
+func (h *Handler) createPartitions(c *fiber.Ctx) error {
+	topicName := c.Params("topicName")
+	if topicName == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
+	}
+
+	var req model.CreatePartitionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := h.topics.CheckPartitions(req.Count); err != nil {
+		return err
+	}
+
+	topic, err := h.client.GetTopic(c.Context(), topicName)
+	if err != nil {
+		h.logger.Error("get topic failed", "topic", topicName, "error", err)
+		return err
+	}
+
+	current := len(topic.Partitions)
+	if int(req.Count) <= current {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("partition count must be greater than current count %d", current))
+	}
+
+	if len(req.ReplicaAssignment) > 0 && len(req.ReplicaAssignment) != int(req.Count)-current {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("replica assignment must list %d new partitions", int(req.Count)-current))
+	}
+
+	keyed, sampleErr := h.sampleKeyed(c.Context(), topic)
+	if sampleErr != nil {
+		h.logger.Warn("sample topic keys failed", "topic", topicName, "error", sampleErr)
+	}
+
+	err = h.client.CreatePartitions(c.Context(), topicName, req.Count, req.ReplicaAssignment)
+	h.recordAudit(c, auth.ResourceTopic, topicName, auth.ActionAlter,
+		fiber.Map{"partitions": current}, fiber.Map{"partitions": req.Count}, err)
+	if err != nil {
+		h.logger.Error("create partitions failed", "topic", topicName, "error", err)
+		return err
+	}
+
+	resp := model.CreatePartitionsResponse{
+		Message:        "partitions increased",
+		PreviousCount:  current,
+		PartitionCount: int(req.Count),
+	}
+	if warning := keyedTopicWarning(topic, keyed, sampleErr); warning != "" {
+		resp.Warnings = append(resp.Warnings, warning)
+	}
+
+	return c.JSON(resp)
+}
+
+// keySampleSize is how many of the latest records are checked for keys
+// before adding partitions.
+const keySampleSize = 20
+
+// sampleKeyed reports whether any of the latest records of a topic has a
+// key. Compacted topics are keyed by definition and are not sampled.
+func (h *Handler) sampleKeyed(ctx context.Context, topic *model.TopicDetail) (bool, error) {
+	if strings.Contains(topic.Configs["cleanup.policy"], "compact") {
+		return true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	page, err := h.client.BrowseMessages(ctx, topic.Name, model.BrowseRequest{Last: keySampleSize}, codec.Renderer{Format: codec.UTF8})
+	if err != nil {
+		return false, err
+	}
+	for _, m := range page.Messages {
+		if m.Key != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// keyedTopicWarning explains that adding partitions changes the
+// key-to-partition mapping, which breaks per-key ordering, when the topic
+// is keyed or could not be sampled.
+func keyedTopicWarning(topic *model.TopicDetail, keyed bool, sampleErr error) string {
+	switch {
+	case sampleErr != nil:
+		return "could not sample records for keys: if producers set keys, key-based partitioning and per-key ordering will change"
+	case strings.Contains(topic.Configs["cleanup.policy"], "compact"):
+		return "topic is keyed (cleanup.policy=compact): key-based partitioning and per-key ordering will change"
+	case keyed:
+		return "topic is keyed (recent records have keys): key-based partitioning and per-key ordering will change"
+	}
+	return ""
+}
+
+func (h *Handler) deleteTopic(c *fiber.Ctx) error {
+	topicName := c.Params("topicName")
+	if topicName == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
+	}
+
+	if strings.HasPrefix(topicName, "_") {
+		return fiber.NewError(fiber.StatusForbidden, "internal topics cannot be deleted")
+	}
+
+	// The caller must echo the topic name back to guard against typos and
+	// accidental requests against the wrong path.
+	if c.Query("confirm") != topicName {
+		return fiber.NewError(fiber.StatusBadRequest, "confirm query parameter must match the topic name")
+	}
+
+	topic, err := h.client.GetTopic(c.Context(), topicName)
+	if err != nil {
+		h.logger.Error("get topic failed", "topic", topicName, "error", err)
+		return err
+	}
+
+	if !c.QueryBool("force", false) {
+		groups, err := h.activeConsumerGroups(c.Context(), topicName)
+		if err != nil {
+			h.logger.Error("list active consumer groups failed", "topic", topicName, "error", err)
+			return err
+		}
+		if len(groups) > 0 {
+			msg := "topic has active consumer groups, use force=true to delete anyway"
+			h.recordAudit(c, auth.ResourceTopic, topicName, auth.ActionDelete, nil, nil, errors.New(msg))
+			return writeError(c, fiber.StatusConflict, "", msg, fiber.Map{"consumer_groups": groups})
+		}
+	}
+
+	err = h.client.DeleteTopic(c.Context(), topicName)
+	h.recordAudit(c, auth.ResourceTopic, topicName, auth.ActionDelete,
+		fiber.Map{"partitions": len(topic.Partitions), "configs": topic.Configs}, nil, err)
+	if err != nil {
+		h.logger.Error("delete topic failed", "topic", topicName, "error", err)
+		return err
+	}
+
+	return c.JSON(fiber.Map{"message": "topic deleted"})
+}
+
+// activeConsumerGroups returns the IDs of groups that have at least one
+// member currently assigned a partition of topic. Only memberships are
+// described; offsets and lag are not needed.
+func (h *Handler) activeConsumerGroups(ctx context.Context, topic string) ([]string, error) {
+	groups, err := h.client.ListConsumerGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if g.State != "Empty" && g.State != "Dead" {
+			ids = append(ids, g.GroupID)
+		}
+	}
+	active := make([]string, 0)
+	if len(ids) == 0 {
+		return active, nil
+	}
+
+	described, err := h.client.DescribeConsumerGroups(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range described {
+	members:
+		for _, m := range g.Members {
+			for _, tp := range m.Assignment {
+				if tp.Topic == topic {
+					active = append(active, g.GroupID)
+					break members
+				}
+			}
+		}
+	}
+	return active, nil
+}
+
 func (h *Handler) listConsumerGroups(c *fiber.Ctx) error {
 	groups, err := h.client.ListConsumerGroups(c.Context())
 	if err != nil {
 		h.logger.Error("list consumer groups failed", "error", err)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
+	}
+
+	visible := groups[:0]
+	for _, g := range groups {
+		if !h.visible(c, auth.ResourceGroup, g.GroupID) {
+			continue
+		}
+		if h.lag != nil {
+			g.Health, _ = h.lag.Status(g.GroupID)
+		}
+		visible = append(visible, g)
 	}
-	return c.JSON(groups)
+	return c.JSON(visible)
 }
 
 func (h *Handler) getConsumerGroup(c *fiber.Ctx) error {
 	groupID := c.Params("groupID")
 	if groupID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "group id required"})
+		return fiber.NewError(fiber.StatusBadRequest, "group id required")
 	}
 
 	group, err := h.client.GetConsumerGroup(c.Context(), groupID)
 	if err != nil {
 		h.logger.Error("get consumer group failed", "group", groupID, "error", err)
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(group)
 }
 
+func (h *Handler) getConsumerGroupHealth(c *fiber.Ctx) error {
+	if h.lag == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "lag evaluation is disabled")
+	}
+
+	groupID := c.Params("groupID")
+	health, ok := h.lag.GroupHealth(groupID)
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("consumer group %s has not been sampled yet", groupID))
+	}
+	return c.JSON(health)
+}
+
+func (h *Handler) deleteConsumerGroup(c *fiber.Ctx) error {
+	groupID := c.Params("groupID")
+	if groupID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "group id required")
+	}
+
+	if err := h.requireEmptyGroup(c.Context(), groupID); err != nil {
+		return err
+	}
+
+	results, err := h.client.DeleteConsumerGroups(c.Context(), []string{groupID})
+	if err != nil {
+		h.recordAudit(c, auth.ResourceGroup, groupID, auth.ActionDelete, nil, nil, err)
+		h.logger.Error("delete consumer group failed", "group", groupID, "error", err)
+		return err
+	}
+
+	status := fiber.StatusOK
+	for _, r := range results {
+		if !r.Deleted {
+			status = fiber.StatusInternalServerError
+			err = errors.New(r.Error)
+		}
+	}
+	h.recordAudit(c, auth.ResourceGroup, groupID, auth.ActionDelete, nil, nil, err)
+	return c.Status(status).JSON(fiber.Map{"results": results})
+}
+
+func (h *Handler) deleteConsumerGroupOffsets(c *fiber.Ctx) error {
+	groupID := c.Params("groupID")
+	if groupID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "group id required")
+	}
+
+	topic := c.Query("topic")
+	if topic == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "topic query parameter required")
+	}
+
+	// Dropping a group's offsets rewinds it on the topic, so the caller must
+	// also be allowed to consume the topic.
+	if err := h.authorize(c, auth.ResourceTopic, topic, auth.ActionConsume); err != nil {
+		return err
+	}
+
+	if err := h.requireEmptyGroup(c.Context(), groupID); err != nil {
+		return err
+	}
+
+	result, err := h.client.DeleteConsumerGroupOffsets(c.Context(), groupID, topic)
+	auditErr := err
+	if err == nil && !result.Deleted {
+		auditErr = errors.New(result.Error)
+	}
+	h.recordAudit(c, auth.ResourceGroup, groupID, auth.ActionResetOffsets, nil, fiber.Map{"deleted_topic": topic}, auditErr)
+	if err != nil {
+		h.logger.Error("delete consumer group offsets failed", "group", groupID, "topic", topic, "error", err)
+		return err
+	}
+
+	status := fiber.StatusOK
+	if !result.Deleted {
+		status = fiber.StatusNotFound
+	}
+	return c.Status(status).JSON(fiber.Map{"results": []model.ConsumerGroupResult{*result}})
+}
+
+// requireEmptyGroup returns an HTTP status and error unless groupID exists
+// and has no active members.
+func (h *Handler) requireEmptyGroup(ctx context.Context, groupID string) error {
+	group, err := h.client.GetConsumerGroup(ctx, groupID)
+	if err != nil {
+		h.logger.Error("get consumer group failed", "group", groupID, "error", err)
+		return err
+	}
+	if group.State == "Dead" {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("consumer group %s not found", groupID))
+	}
+	if group.State != "Empty" {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("consumer group must be empty, current state is %s", group.State))
+	}
+	return nil
+}
+
+func (h *Handler) resetConsumerGroupOffsets(c *fiber.Ctx) error {
+	groupID := c.Params("groupID")
+	if groupID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "group id required")
+	}
+
+	var req model.ResetOffsetsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if len(req.Partitions) > 0 && req.Topic == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "topic required when partitions are given")
+	}
+
+	if req.Strategy == model.ResetToDatetime {
+		t, err := time.Parse(time.RFC3339, req.Datetime)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "datetime must be RFC 3339")
+		}
+		ts := t.UnixMilli()
+		req.Timestamp = &ts
+	}
+
+	group, err := h.client.GetConsumerGroup(c.Context(), groupID)
+	if err != nil {
+		h.logger.Error("get consumer group failed", "group", groupID, "error", err)
+		return err
+	}
+
+	// Committing offsets underneath live members would be overwritten by
+	// their next commit, so like kafka-consumer-groups we only reset
+	// inactive groups.
+	if group.State != "Empty" && group.State != "Dead" {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("consumer group must be inactive to reset offsets, current state is %s", group.State))
+	}
+
+	// Moving offsets decides what the group reads next, so the caller must
+	// also be allowed to consume every topic the reset touches.
+	topics := []string{req.Topic}
+	if req.Topic == "" {
+		topics = topics[:0]
+		for _, t := range group.Topics {
+			topics = append(topics, t.Topic)
+		}
+	}
+	for _, topic := range topics {
+		if err := h.authorize(c, auth.ResourceTopic, topic, auth.ActionConsume); err != nil {
+			return err
+		}
+	}
+
+	resets, err := h.client.ResetConsumerGroupOffsets(c.Context(), groupID, req)
+	if !req.DryRun {
+		h.recordAudit(c, auth.ResourceGroup, groupID, auth.ActionResetOffsets, nil, fiber.Map{
+			"strategy": req.Strategy, "topic": req.Topic, "partitions": resets,
+		}, err)
+	}
+	if err != nil {
+		h.logger.Error("reset consumer group offsets failed", "group", groupID, "error", err)
+		return err
+	}
+
+	return c.JSON(model.ResetOffsetsResponse{
+		GroupID:    groupID,
+		DryRun:     req.DryRun,
+		Partitions: resets,
+	})
+}
+
+func (h *Handler) produceMessages(c *fiber.Ctx) error {
+	topicName := c.Params("topicName")
+	if topicName == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
+	}
+
+	var req model.ProduceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	inline := req.Key != nil || req.Value != nil || len(req.Headers) > 0 || req.Partition != nil || req.Timestamp != 0
+	switch {
+	case inline && len(req.Messages) > 0:
+		return fiber.NewError(fiber.StatusBadRequest, "send either a single message or messages, not both")
+	case req.Key == nil && req.Value == nil && len(req.Messages) == 0:
+		return fiber.NewError(fiber.StatusBadRequest, "key, value or messages required")
+	}
+	records := req.Records()
+
+	timeout, _ := strconv.Atoi(c.Query("timeout", "10"))
+	if timeout <= 0 {
+		timeout = 10
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	results, err := h.client.Produce(ctx, topicName, records)
+	if err != nil {
+		h.logger.Error("produce failed", "topic", topicName, "error", err)
+		status, code := errorStatus(err)
+		return writeError(c, status, code, err.Error(), fiber.Map{"results": results})
+	}
+
+	status := fiber.StatusOK
+	for _, r := range results {
+		if r.Error != "" {
+			status = fiber.StatusInternalServerError
+			break
+		}
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"topic":   topicName,
+		"count":   len(results),
+		"results": results,
+	})
+}
+
 func (h *Handler) consumeMessagesBatch(c *fiber.Ctx) error {
 	topicName := c.Params("topicName")
 	if topicName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "topic name required"})
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
 	}
 
 	groupID := c.Query("group_id", "kafka-admin-api-batch-consumer")
+	if err := h.authorize(c, auth.ResourceGroup, groupID, auth.ActionConsume); err != nil {
+		return err
+	}
 	autoOffset := c.Query("offset", "earliest")
+	format := c.Query("format", codec.UTF8)
+	if !codec.Valid(format) {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be one of utf8, base64, hex, json")
+	}
 	maxMessagesStr := c.Query("max", "10")
 	timeoutStr := c.Query("timeout", "5")
 
@@ -206,15 +937,16 @@ func (h *Handler) consumeMessagesBatch(c *fiber.Ctx) error {
 
 	consumer, err := h.client.CreateConsumer(groupID, autoOffset)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 	defer consumer.Close()
 
 	if err := consumer.Subscribe(topicName, nil); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return err
 	}
 
 	messages := make([]model.Message, 0, maxMessages)
+	renderer := codec.Renderer{Format: format, Registry: h.registry}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
 	defer cancel()
 
@@ -231,20 +963,7 @@ func (h *Handler) consumeMessagesBatch(c *fiber.Ctx) error {
 				continue
 			}
 
-			headers := make(map[string]string)
-			for _, hdr := range msg.Headers {
-				headers[hdr.Key] = string(hdr.Value)
-			}
-
-			messages = append(messages, model.Message{
-				Topic:     *msg.TopicPartition.Topic,
-				Partition: msg.TopicPartition.Partition,
-				Offset:    int64(msg.TopicPartition.Offset),
-				Key:       string(msg.Key),
-				Value:     string(msg.Value),
-				Timestamp: msg.Timestamp.UnixMilli(),
-				Headers:   headers,
-			})
+			messages = append(messages, renderer.Message(ctx, msg))
 		}
 	}
 
@@ -258,14 +977,190 @@ done:
 	})
 }
 
+// browseMessages pages through a topic with manual partition assignment,
+// so peeking at messages never creates a consumer group or moves offsets.
+func (h *Handler) browseMessages(c *fiber.Ctx) error {
+	topicName := c.Params("topicName")
+	if topicName == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
+	}
+
+	format := c.Query("format", codec.UTF8)
+	if !codec.Valid(format) {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be one of utf8, base64, hex, json")
+	}
+	req, err := parseBrowseRequest(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	timeout, _ := strconv.Atoi(c.Query("timeout", "5"))
+	if timeout <= 0 {
+		timeout = 5
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	page, err := h.client.BrowseMessages(ctx, topicName, req, codec.Renderer{Format: format, Registry: h.registry})
+	if err != nil {
+		h.logger.Error("browse messages failed", "topic", topicName, "error", err)
+		return err
+	}
+
+	page.NextCursor = encodeCursor(page.NextOffsets)
+	return c.JSON(page)
+}
+
+func parseBrowseRequest(c *fiber.Ctx) (model.BrowseRequest, error) {
+	req := model.BrowseRequest{Limit: 10}
+
+	if v := c.Query("partitions"); v != "" {
+		for _, part := range strings.Split(v, ",") {
+			p, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+			if err != nil || p < 0 {
+				return req, fmt.Errorf("invalid partition %q", part)
+			}
+			req.Partitions = append(req.Partitions, int32(p))
+		}
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > 500 {
+			return req, fmt.Errorf("limit must be between 1 and 500")
+		}
+		req.Limit = limit
+	}
+
+	starts := 0
+	if v := c.Query("offset"); v != "" {
+		offset, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || offset < 0 {
+			return req, fmt.Errorf("invalid offset %q", v)
+		}
+		req.Offset = &offset
+		starts++
+	}
+	if v := c.Query("timestamp"); v != "" {
+		ts, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ts < 0 {
+			return req, fmt.Errorf("invalid timestamp %q", v)
+		}
+		req.Timestamp = &ts
+		starts++
+	}
+	if v := c.Query("last"); v != "" {
+		last, err := strconv.Atoi(v)
+		if err != nil || last <= 0 || last > 500 {
+			return req, fmt.Errorf("last must be between 1 and 500")
+		}
+		req.Last = last
+		starts++
+	}
+	if v := c.Query("cursor"); v != "" {
+		cursor, err := decodeCursor(v)
+		if err != nil {
+			return req, err
+		}
+		req.Cursor = cursor
+		starts++
+	}
+	if starts > 1 {
+		return req, fmt.Errorf("only one of offset, timestamp, last and cursor may be given")
+	}
+
+	// The cursor carries every partition of the first page. Reading other
+	// partitions would restart them at the low watermark and mix pages.
+	if req.Cursor != nil {
+		partitions := cursorPartitions(req.Cursor)
+		if req.Partitions != nil && !samePartitions(req.Partitions, partitions) {
+			return req, fmt.Errorf("partitions must match the cursor")
+		}
+		req.Partitions = partitions
+	}
+
+	return req, nil
+}
+
+// encodeCursor packs the next offset of every partition into an opaque,
+// URL-safe page token.
+func encodeCursor(offsets map[int32]int64) string {
+	partitions := cursorPartitions(offsets)
+	parts := make([]string, 0, len(partitions))
+	for _, p := range partitions {
+		parts = append(parts, fmt.Sprintf("%d:%d", p, offsets[p]))
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(parts, ",")))
+}
+
+// cursorPartitions returns the partitions of a cursor in ascending order.
+func cursorPartitions(offsets map[int32]int64) []int32 {
+	partitions := make([]int32, 0, len(offsets))
+	for p := range offsets {
+		partitions = append(partitions, p)
+	}
+	sort.Slice(partitions, func(i, j int) bool { return partitions[i] < partitions[j] })
+	return partitions
+}
+
+func samePartitions(requested, sorted []int32) bool {
+	if len(requested) != len(sorted) {
+		return false
+	}
+	r := append([]int32(nil), requested...)
+	sort.Slice(r, func(i, j int) bool { return r[i] < r[j] })
+	for i := range r {
+		if r[i] != sorted[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func decodeCursor(cursor string) (map[int32]int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	offsets := make(map[int32]int64)
+	for _, part := range strings.Split(string(raw), ",") {
+		p, o, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		partition, err := strconv.ParseInt(p, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		offset, err := strconv.ParseInt(o, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		offsets[int32(partition)] = offset
+	}
+	return offsets, nil
+}
+
 func (h *Handler) consumeMessagesSSE(c *fiber.Ctx) error {
 	topicName := c.Params("topicName")
 	if topicName == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "topic name required"})
+		return fiber.NewError(fiber.StatusBadRequest, "topic name required")
 	}
 
 	groupID := c.Query("group_id", "kafka-admin-api-sse-consumer")
+	if err := h.authorize(c, auth.ResourceGroup, groupID, auth.ActionConsume); err != nil {
+		return err
+	}
 	autoOffset := c.Query("offset", "earliest")
+	format := c.Query("format", codec.UTF8)
+	if !codec.Valid(format) {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be one of utf8, base64, hex, json")
+	}
 	maxMessagesStr := c.Query("max", "0")
 
 	maxMessages, _ := strconv.Atoi(maxMessagesStr)
@@ -285,8 +1180,18 @@ func (h *Handler) consumeMessagesSSE(c *fiber.Ctx) error {
 	// Capture variables for closure
 	client := h.client
 	logger := h.logger
+	renderer := codec.Renderer{Format: format, Registry: h.registry}
+	stats := h.metrics
 
 	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		stats.SSEConsumerStarted()
+		defer stats.SSEConsumerStopped()
+
+		// The request context is recycled once the handler returns, so the
+		// stream gets its own, cancelled when the client goes away.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
 		consumer, err := client.CreateConsumer(groupID, autoOffset)
 		if err != nil {
 			logger.Error("failed to create consumer", "error", err)
@@ -322,20 +1227,7 @@ func (h *Handler) consumeMessagesSSE(c *fiber.Ctx) error {
 				continue
 			}
 
-			headers := make(map[string]string)
-			for _, hdr := range msg.Headers {
-				headers[hdr.Key] = string(hdr.Value)
-			}
-
-			m := model.Message{
-				Topic:     *msg.TopicPartition.Topic,
-				Partition: msg.TopicPartition.Partition,
-				Offset:    int64(msg.TopicPartition.Offset),
-				Key:       string(msg.Key),
-				Value:     string(msg.Value),
-				Timestamp: msg.Timestamp.UnixMilli(),
-				Headers:   headers,
-			}
+			m := renderer.Message(ctx, msg)
 
 			data, _ := json.Marshal(m)
 			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
@@ -345,6 +1237,7 @@ func (h *Handler) consumeMessagesSSE(c *fiber.Ctx) error {
 			}
 
 			count++
+			stats.MessageStreamed()
 			logger.Info("SSE message sent", "count", count, "offset", m.Offset)
 
 			if maxMessages > 0 && count >= maxMessages {
@@ -2,19 +2,38 @@ package handler
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"log/slog"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"kafka-admin-api/internal/audit"
+	"kafka-admin-api/internal/auth"
+	"kafka-admin-api/internal/codec"
+	kafkaclient "kafka-admin-api/internal/kafka"
+	"kafka-admin-api/internal/lag"
+	"kafka-admin-api/internal/metrics"
 	"kafka-admin-api/internal/model"
+	"kafka-admin-api/internal/topicpolicy"
 )
 
 // Mock Kafka Client
@@ -27,6 +46,14 @@ func (m *MockKafkaClient) ListBrokers(ctx context.Context) ([]model.Broker, erro
 	return args.Get(0).([]model.Broker), args.Error(1)
 }
 
+func (m *MockKafkaClient) DescribeCluster(ctx context.Context) (*model.Cluster, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Cluster), args.Error(1)
+}
+
 func (m *MockKafkaClient) ListTopics(ctx context.Context) ([]model.Topic, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]model.Topic), args.Error(1)
@@ -50,6 +77,16 @@ func (m *MockKafkaClient) UpdateTopicConfig(ctx context.Context, name string, co
 	return args.Error(0)
 }
 
+func (m *MockKafkaClient) CreatePartitions(ctx context.Context, name string, count int32, assignment [][]int32) error {
+	args := m.Called(ctx, name, count, assignment)
+	return args.Error(0)
+}
+
+func (m *MockKafkaClient) DeleteTopic(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
 func (m *MockKafkaClient) ListConsumerGroups(ctx context.Context) ([]model.ConsumerGroup, error) {
 	args := m.Called(ctx)
 	return args.Get(0).([]model.ConsumerGroup), args.Error(1)
@@ -63,6 +100,127 @@ func (m *MockKafkaClient) GetConsumerGroup(ctx context.Context, groupID string)
 	return args.Get(0).(*model.ConsumerGroupDetail), args.Error(1)
 }
 
+func (m *MockKafkaClient) DescribeConsumerGroups(ctx context.Context, groupIDs []string) ([]model.ConsumerGroupDetail, error) {
+	args := m.Called(ctx, groupIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ConsumerGroupDetail), args.Error(1)
+}
+
+func (m *MockKafkaClient) ResetConsumerGroupOffsets(ctx context.Context, groupID string, req model.ResetOffsetsRequest) ([]model.OffsetReset, error) {
+	args := m.Called(ctx, groupID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.OffsetReset), args.Error(1)
+}
+
+func (m *MockKafkaClient) DeleteConsumerGroups(ctx context.Context, groupIDs []string) ([]model.ConsumerGroupResult, error) {
+	args := m.Called(ctx, groupIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ConsumerGroupResult), args.Error(1)
+}
+
+func (m *MockKafkaClient) DeleteConsumerGroupOffsets(ctx context.Context, groupID, topic string) (*model.ConsumerGroupResult, error) {
+	args := m.Called(ctx, groupID, topic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ConsumerGroupResult), args.Error(1)
+}
+
+func (m *MockKafkaClient) Produce(ctx context.Context, topic string, messages []model.ProduceMessage) ([]model.ProduceResult, error) {
+	args := m.Called(ctx, topic, messages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProduceResult), args.Error(1)
+}
+
+func (m *MockKafkaClient) DescribeScramUsers(ctx context.Context, users []string) ([]model.ScramUser, error) {
+	args := m.Called(ctx, users)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ScramUser), args.Error(1)
+}
+
+func (m *MockKafkaClient) UpsertScramCredential(ctx context.Context, user, mechanism string, iterations int, password []byte) error {
+	args := m.Called(ctx, user, mechanism, iterations, password)
+	return args.Error(0)
+}
+
+func (m *MockKafkaClient) DeleteScramCredentials(ctx context.Context, user string, mechanisms []string) error {
+	args := m.Called(ctx, user, mechanisms)
+	return args.Error(0)
+}
+
+func (m *MockKafkaClient) DescribeTopicConfigs(ctx context.Context, topic string) ([]model.ConfigEntry, error) {
+	args := m.Called(ctx, topic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ConfigEntry), args.Error(1)
+}
+
+func (m *MockKafkaClient) DescribeBrokerConfigs(ctx context.Context, broker string) ([]model.ConfigEntry, error) {
+	args := m.Called(ctx, broker)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ConfigEntry), args.Error(1)
+}
+
+func (m *MockKafkaClient) AlterBrokerConfigs(ctx context.Context, broker string, configs map[string]string) error {
+	args := m.Called(ctx, broker, configs)
+	return args.Error(0)
+}
+
+func (m *MockKafkaClient) DescribeClientQuotas(ctx context.Context, filter model.QuotaEntity) ([]model.ClientQuota, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ClientQuota), args.Error(1)
+}
+
+func (m *MockKafkaClient) AlterClientQuotas(ctx context.Context, entity model.QuotaEntity, set map[string]float64, remove []string) error {
+	args := m.Called(ctx, entity, set, remove)
+	return args.Error(0)
+}
+
+func (m *MockKafkaClient) ListACLs(ctx context.Context, filter model.ACLFilter) ([]model.ACL, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ACL), args.Error(1)
+}
+
+func (m *MockKafkaClient) CreateACLs(ctx context.Context, acls []model.ACL) error {
+	args := m.Called(ctx, acls)
+	return args.Error(0)
+}
+
+func (m *MockKafkaClient) DeleteACLs(ctx context.Context, filter model.ACLFilter) ([]model.ACL, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ACL), args.Error(1)
+}
+
+func (m *MockKafkaClient) BrowseMessages(ctx context.Context, topic string, req model.BrowseRequest, renderer codec.Renderer) (*model.BrowsePage, error) {
+	args := m.Called(ctx, topic, req, renderer)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.BrowsePage), args.Error(1)
+}
+
 func (m *MockKafkaClient) CreateConsumer(groupID, autoOffset string) (*kafka.Consumer, error) {
 	args := m.Called(groupID, autoOffset)
 	if args.Get(0) == nil {
@@ -86,18 +244,101 @@ func setupTestApp(mockClient *MockKafkaClient) *fiber.App {
 	return app
 }
 
+// setupAdminApp is setupTestApp with authentication on. Every request
+// carries an API key holding every scope, as the credential, ACL, quota and
+// broker config routes refuse to run without authentication.
+func setupAdminApp(t *testing.T, mockClient *MockKafkaClient) *fiber.App {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	keysFile := "keys:\n  - name: admin\n    hash: " + auth.HashKey("admin-key") + "\n" +
+		"    scopes: [read-metadata, manage-acls, manage-users, manage-quotas, manage-cluster]\n"
+	require.NoError(t, os.WriteFile(path, []byte(keysFile), 0o600))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	keys, err := auth.LoadKeyStore(path, logger)
+	require.NoError(t, err)
+
+	h := New(mockClient, logger, WithAPIKeys(keys))
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Request().Header.Set(apiKeyHeader, "admin-key")
+		return c.Next()
+	})
+	h.SetupRoutes(app)
+	return app
+}
+
 func TestHealth(t *testing.T) {
 	app := fiber.New()
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	h := &Handler{logger: logger}
-	app.Get("/health", h.health)
+	app.Get("/health/live", h.live)
 
-	req := httptest.NewRequest("GET", "/health", nil)
+	req := httptest.NewRequest("GET", "/health/live", nil)
 	resp, err := app.Test(req)
 	assert.NoError(t, err)
 	assert.Equal(t, 200, resp.StatusCode)
 }
 
+func TestReadiness(t *testing.T) {
+	// A local listener stands in for the controller.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	addr := listener.Addr().(*net.TCPAddr)
+
+	mockClient := new(MockKafkaClient)
+	mockClient.On("DescribeCluster", mock.Anything).Return(&model.Cluster{
+		ControllerID: 1,
+		Brokers: []model.Broker{
+			{ID: 1, Host: "127.0.0.1", Port: int32(addr.Port)},
+			{ID: 2, Host: "broker-2", Port: 9092},
+		},
+	}, nil)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := New(mockClient, logger, WithReadiness(3, time.Second))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	req := httptest.NewRequest("GET", "/health/ready", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var readiness model.Readiness
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&readiness))
+	assert.Equal(t, "ready", readiness.Status)
+	assert.Equal(t, 2, readiness.Brokers)
+	assert.Len(t, readiness.Checks, 3)
+	assert.Equal(t, model.CheckDegraded, readiness.Checks[1].Status)
+	assert.Equal(t, model.CheckOK, readiness.Checks[2].Status)
+}
+
+func TestReadinessClusterUnreachable(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("DescribeCluster", mock.Anything).Return(nil, errors.New("describe cluster: Local: Timed out"))
+
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("GET", "/health/ready", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+
+	var readiness model.Readiness
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&readiness))
+	assert.Equal(t, "not_ready", readiness.Status)
+	assert.Equal(t, "metadata", readiness.Checks[0].Name)
+	assert.Contains(t, readiness.Checks[0].Detail, "Timed out")
+
+	// Liveness is unaffected by the cluster being down.
+	req = httptest.NewRequest("GET", "/health", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
 func TestListBrokers(t *testing.T) {
 	mockClient := new(MockKafkaClient)
 	mockClient.On("ListBrokers", mock.Anything).Return([]model.Broker{
@@ -190,6 +431,59 @@ func TestGetTopic(t *testing.T) {
 	assert.Equal(t, "test-topic", topic.Name)
 }
 
+func TestGetTopicIncludeAll(t *testing.T) {
+	value, broker := "86400000", "86400000"
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetTopic", mock.Anything, "orders").Return(&model.TopicDetail{
+		Name:    "orders",
+		Configs: map[string]string{},
+	}, nil)
+	mockClient.On("DescribeTopicConfigs", mock.Anything, "orders").Return([]model.ConfigEntry{
+		{Name: "retention.ms", Value: &value, Source: model.ConfigSourceDynamicBroker, Synonyms: []model.ConfigSynonym{
+			{Name: "log.retention.ms", Value: &broker, Source: model.ConfigSourceDynamicBroker},
+		}},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/topics/orders?include=all", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var topic model.TopicDetail
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&topic))
+	require.Len(t, topic.ConfigEntries, 1)
+	assert.Equal(t, "dynamic-broker", topic.ConfigEntries[0].Source)
+	assert.Equal(t, "log.retention.ms", topic.ConfigEntries[0].Synonyms[0].Name)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/topics/orders?include=everything", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestGetTopicConfig(t *testing.T) {
+	value := "delete"
+	mockClient := new(MockKafkaClient)
+	mockClient.On("DescribeTopicConfigs", mock.Anything, "orders").Return([]model.ConfigEntry{
+		{Name: "cleanup.policy", Value: &value, Source: model.ConfigSourceDefault},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/topics/orders/configs/cleanup.policy", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var entry model.ConfigEntry
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&entry))
+	assert.Equal(t, "delete", *entry.Value)
+	assert.Equal(t, "default", entry.Source)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/topics/orders/configs/no.such.key", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
 func TestListConsumerGroups(t *testing.T) {
 	mockClient := new(MockKafkaClient)
 	mockClient.On("ListConsumerGroups", mock.Anything).Return([]model.ConsumerGroup{
@@ -208,3 +502,1401 @@ func TestListConsumerGroups(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, groups, 1)
 }
+
+func TestDeleteTopic(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetTopic", mock.Anything, "test-topic").Return(&model.TopicDetail{Name: "test-topic"}, nil)
+	mockClient.On("ListConsumerGroups", mock.Anything).Return([]model.ConsumerGroup{
+		{GroupID: "group-1", State: "Empty"},
+	}, nil)
+	mockClient.On("DeleteTopic", mock.Anything, "test-topic").Return(nil)
+
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("DELETE", "/topics/test-topic?confirm=test-topic", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockClient.AssertCalled(t, "DeleteTopic", mock.Anything, "test-topic")
+}
+
+func TestDeleteTopicGuards(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("DELETE", "/topics/__consumer_offsets?confirm=__consumer_offsets", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/topics/test-topic?confirm=other-topic", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	mockClient.AssertNotCalled(t, "DeleteTopic", mock.Anything, mock.Anything)
+}
+
+func TestDeleteTopicActiveConsumerGroup(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetTopic", mock.Anything, "test-topic").Return(&model.TopicDetail{Name: "test-topic"}, nil)
+	mockClient.On("ListConsumerGroups", mock.Anything).Return([]model.ConsumerGroup{
+		{GroupID: "group-1", State: "Stable"},
+	}, nil)
+	mockClient.On("DescribeConsumerGroups", mock.Anything, []string{"group-1"}).Return([]model.ConsumerGroupDetail{{
+		GroupID: "group-1",
+		State:   "Stable",
+		Members: []model.Member{
+			{MemberID: "member-1", Assignment: []model.TopicPartition{{Topic: "test-topic", Partition: 0}}},
+		},
+	}}, nil)
+	mockClient.On("DeleteTopic", mock.Anything, "test-topic").Return(nil)
+
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("DELETE", "/topics/test-topic?confirm=test-topic", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+	mockClient.AssertNotCalled(t, "DeleteTopic", mock.Anything, "test-topic")
+	mockClient.AssertNotCalled(t, "GetConsumerGroup", mock.Anything, mock.Anything)
+
+	req = httptest.NewRequest("DELETE", "/topics/test-topic?confirm=test-topic&force=true", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockClient.AssertCalled(t, "DeleteTopic", mock.Anything, "test-topic")
+}
+
+func TestCreatePartitions(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetTopic", mock.Anything, "test-topic").Return(&model.TopicDetail{
+		Name: "test-topic",
+		Partitions: []model.Partition{
+			{ID: 0, Leader: 1}, {ID: 1, Leader: 2}, {ID: 2, Leader: 3},
+		},
+		Configs: map[string]string{"cleanup.policy": "compact"},
+	}, nil)
+	mockClient.On("CreatePartitions", mock.Anything, "test-topic", int32(6), [][]int32(nil)).Return(nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `{"count": 6}`
+	req := httptest.NewRequest("POST", "/topics/test-topic/partitions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result model.CreatePartitionsResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.PreviousCount)
+	assert.Equal(t, 6, result.PartitionCount)
+	assert.Len(t, result.Warnings, 1)
+}
+
+func TestCreatePartitionsSamplesKeys(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		messages []model.Message
+		err      error
+		warning  string
+	}{
+		{name: "keyed", messages: []model.Message{{Offset: 1}, {Offset: 2, Key: "customer-7"}}, warning: "recent records have keys"},
+		{name: "unkeyed", messages: []model.Message{{Offset: 1}, {Offset: 2}}},
+		{name: "sampling failed", err: context.DeadlineExceeded, warning: "could not sample records"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := new(MockKafkaClient)
+			mockClient.On("GetTopic", mock.Anything, "orders").Return(&model.TopicDetail{
+				Name:       "orders",
+				Partitions: []model.Partition{{ID: 0}},
+				Configs:    map[string]string{},
+			}, nil)
+			page := &model.BrowsePage{Topic: "orders", Messages: tc.messages}
+			if tc.err != nil {
+				page = nil
+			}
+			mockClient.On("BrowseMessages", mock.Anything, "orders", mock.MatchedBy(func(req model.BrowseRequest) bool {
+				return req.Last > 0
+			}), mock.Anything).Return(page, tc.err)
+			mockClient.On("CreatePartitions", mock.Anything, "orders", int32(2), [][]int32(nil)).Return(nil)
+
+			app := setupTestApp(mockClient)
+
+			req := httptest.NewRequest("POST", "/topics/orders/partitions", strings.NewReader(`{"count": 2}`))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.StatusCode)
+
+			var result model.CreatePartitionsResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			if tc.warning == "" {
+				assert.Empty(t, result.Warnings)
+				return
+			}
+			require.Len(t, result.Warnings, 1)
+			assert.Contains(t, result.Warnings[0], tc.warning)
+		})
+	}
+}
+
+func TestCreatePartitionsValidation(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetTopic", mock.Anything, "test-topic").Return(&model.TopicDetail{
+		Name:       "test-topic",
+		Partitions: []model.Partition{{ID: 0}, {ID: 1}, {ID: 2}},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	for _, body := range []string{
+		`{"count": 3}`,
+		`{"count": 5, "replica_assignment": [[1, 2, 3]]}`,
+	} {
+		req := httptest.NewRequest("POST", "/topics/test-topic/partitions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, body)
+	}
+
+	mockClient.AssertNotCalled(t, "CreatePartitions", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetConsumerGroupOffsetsRequiresStrategyValue(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	app := setupTestApp(mockClient)
+
+	for _, body := range []string{
+		`{"strategy": "to-offset"}`,
+		`{"strategy": "to-timestamp"}`,
+		`{"strategy": "to-datetime"}`,
+		`{"strategy": "shift-by"}`,
+	} {
+		req := httptest.NewRequest("POST", "/consumer-groups/group-1/offsets/reset", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, body)
+	}
+
+	mockClient.AssertNotCalled(t, "ResetConsumerGroupOffsets", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetConsumerGroupOffsetsDryRun(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetConsumerGroup", mock.Anything, "group-1").Return(&model.ConsumerGroupDetail{
+		GroupID: "group-1",
+		State:   "Empty",
+	}, nil)
+	mockClient.On("ResetConsumerGroupOffsets", mock.Anything, "group-1", mock.MatchedBy(func(req model.ResetOffsetsRequest) bool {
+		return req.Strategy == model.ResetToDatetime && req.DryRun && *req.Timestamp == 1767225600000
+	})).Return([]model.OffsetReset{
+		{Topic: "orders", Partition: 0, PreviousOffset: 100, NewOffset: 40},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `{"strategy": "to-datetime", "datetime": "2026-01-01T00:00:00Z", "topic": "orders", "dry_run": true}`
+	req := httptest.NewRequest("POST", "/consumer-groups/group-1/offsets/reset", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result model.ResetOffsetsResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Len(t, result.Partitions, 1)
+	assert.Equal(t, int64(40), result.Partitions[0].NewOffset)
+}
+
+func TestResetConsumerGroupOffsetsActiveGroup(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetConsumerGroup", mock.Anything, "group-1").Return(&model.ConsumerGroupDetail{
+		GroupID: "group-1",
+		State:   "Stable",
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `{"strategy": "to-earliest"}`
+	req := httptest.NewRequest("POST", "/consumer-groups/group-1/offsets/reset", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+	mockClient.AssertNotCalled(t, "ResetConsumerGroupOffsets", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetConsumerGroupOffsetsInvalidStrategy(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	app := setupTestApp(mockClient)
+
+	body := `{"strategy": "to-nowhere"}`
+	req := httptest.NewRequest("POST", "/consumer-groups/group-1/offsets/reset", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestDeleteConsumerGroup(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetConsumerGroup", mock.Anything, "group-1").Return(&model.ConsumerGroupDetail{
+		GroupID: "group-1",
+		State:   "Empty",
+	}, nil)
+	mockClient.On("DeleteConsumerGroups", mock.Anything, []string{"group-1"}).Return([]model.ConsumerGroupResult{
+		{GroupID: "group-1", Deleted: true},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("DELETE", "/consumer-groups/group-1", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Results []model.ConsumerGroupResult `json:"results"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Results, 1)
+	assert.True(t, result.Results[0].Deleted)
+}
+
+func TestDeleteConsumerGroupNotEmpty(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetConsumerGroup", mock.Anything, "group-1").Return(&model.ConsumerGroupDetail{
+		GroupID: "group-1",
+		State:   "Stable",
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("DELETE", "/consumer-groups/group-1", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+
+	req = httptest.NewRequest("DELETE", "/consumer-groups/group-1/offsets?topic=orders", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+
+	mockClient.AssertNotCalled(t, "DeleteConsumerGroups", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "DeleteConsumerGroupOffsets", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteConsumerGroupOffsets(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetConsumerGroup", mock.Anything, mock.Anything).Return(&model.ConsumerGroupDetail{
+		GroupID: "group-1",
+		State:   "Empty",
+	}, nil)
+	mockClient.On("DeleteConsumerGroupOffsets", mock.Anything, "group-1", "orders").
+		Return(&model.ConsumerGroupResult{GroupID: "group-1", Topic: "orders", Deleted: true}, nil)
+	mockClient.On("DeleteConsumerGroupOffsets", mock.Anything, "group-1", "payments").
+		Return(&model.ConsumerGroupResult{GroupID: "group-1", Topic: "payments", Error: "group has no committed offsets for topic payments"}, nil)
+	mockClient.On("DeleteConsumerGroupOffsets", mock.Anything, "group-1", "refunds").
+		Return(nil, &kafkaclient.Error{
+			Op:  "delete consumer group offsets",
+			Err: kafka.NewError(kafka.ErrGroupSubscribedToTopic, "group is subscribed to the topic", false),
+		})
+
+	app := setupTestApp(mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/consumer-groups/group-1/offsets?topic=orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	var body struct {
+		Results []model.ConsumerGroupResult `json:"results"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Results, 1)
+	assert.True(t, body.Results[0].Deleted)
+	assert.Equal(t, "orders", body.Results[0].Topic)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/consumer-groups/group-1/offsets?topic=payments", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/consumer-groups/group-1/offsets?topic=refunds", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 409, resp.StatusCode)
+
+	mockClient.AssertNotCalled(t, "DeleteConsumerGroups", mock.Anything, mock.Anything)
+}
+
+func TestProduceSingleMessage(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("Produce", mock.Anything, "orders", mock.MatchedBy(func(msgs []model.ProduceMessage) bool {
+		return len(msgs) == 1 && *msgs[0].Key == "order-1" && *msgs[0].Value == "created" && *msgs[0].Partition == 2
+	})).Return([]model.ProduceResult{{Partition: 2, Offset: 41}}, nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `{"key": "order-1", "value": "created", "partition": 2, "headers": {"source": "test"}}`
+	req := httptest.NewRequest("POST", "/topics/orders/messages", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result struct {
+		Count   int                   `json:"count"`
+		Results []model.ProduceResult `json:"results"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Count)
+	assert.Equal(t, int64(41), result.Results[0].Offset)
+}
+
+func TestProduceBatch(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("Produce", mock.Anything, "orders", mock.MatchedBy(func(msgs []model.ProduceMessage) bool {
+		return len(msgs) == 2 && msgs[1].Value == nil
+	})).Return([]model.ProduceResult{
+		{Partition: 0, Offset: 10},
+		{Partition: 1, Offset: -1, Error: "Broker: Message size too large"},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `{"messages": [{"key": "a", "value": "1"}, {"key": "b", "value": null}]}`
+	req := httptest.NewRequest("POST", "/topics/orders/messages", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+}
+
+func TestProduceRejectsEmptyOrMixedBodies(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	app := setupTestApp(mockClient)
+
+	for _, body := range []string{
+		`{}`,
+		`{"messages": []}`,
+		`{"value": "1", "messages": [{"value": "2"}]}`,
+	} {
+		req := httptest.NewRequest("POST", "/topics/orders/messages", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, body)
+	}
+
+	mockClient.AssertNotCalled(t, "Produce", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBrowseMessages(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("BrowseMessages", mock.Anything, "orders", mock.MatchedBy(func(req model.BrowseRequest) bool {
+		return req.Last == 5 && len(req.Partitions) == 2 && req.Cursor == nil
+	}), mock.MatchedBy(func(renderer codec.Renderer) bool {
+		return renderer.Format == codec.Hex
+	})).Return(&model.BrowsePage{
+		Topic: "orders",
+		Count: 1,
+		Messages: []model.Message{
+			{Topic: "orders", Partition: 0, Offset: 99, Value: json.RawMessage(`"v"`)},
+		},
+		NextOffsets: map[int32]int64{0: 100, 1: 20},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("GET", "/topics/orders/browse?partitions=0,1&last=5&format=hex", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var page model.BrowsePage
+	err = json.NewDecoder(resp.Body).Decode(&page)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, page.Count)
+
+	cursor, err := decodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]int64{0: 100, 1: 20}, cursor)
+}
+
+func TestBrowseMessagesCursorKeepsPartitions(t *testing.T) {
+	cursor := encodeCursor(map[int32]int64{1: 20, 3: 7})
+	mockClient := new(MockKafkaClient)
+	mockClient.On("BrowseMessages", mock.Anything, "orders", mock.MatchedBy(func(req model.BrowseRequest) bool {
+		return assert.ObjectsAreEqual([]int32{1, 3}, req.Partitions) && req.Cursor[3] == 7
+	}), mock.Anything).Return(&model.BrowsePage{Topic: "orders", NextOffsets: map[int32]int64{1: 20, 3: 7}}, nil)
+
+	app := setupTestApp(mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/topics/orders/browse?cursor="+cursor, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/topics/orders/browse?partitions=3,1&cursor="+cursor, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/topics/orders/browse?partitions=0,1,3&cursor="+cursor, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestBrowseMessagesInvalidParams(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	app := setupTestApp(mockClient)
+
+	for _, query := range []string{
+		"offset=10&last=5",
+		"partitions=a",
+		"limit=0",
+		"cursor=!!!",
+		"format=xml",
+	} {
+		req := httptest.NewRequest("GET", "/topics/orders/browse?"+query, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, query)
+	}
+}
+
+func TestConsumeMessagesInvalidFormat(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	app := setupTestApp(mockClient)
+
+	for _, path := range []string{
+		"/topics/orders/consume?format=avro",
+		"/topics/orders/messages?format=avro",
+		"/topics/orders/browse?format=avro",
+	} {
+		req := httptest.NewRequest("GET", path, nil)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, 400, resp.StatusCode, path)
+	}
+	mockClient.AssertNotCalled(t, "CreateConsumer", mock.Anything, mock.Anything)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListBrokers", mock.Anything).Return([]model.Broker{{ID: 1, Host: "broker-1", Port: 9092}}, nil)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := New(mockClient, logger, WithMetrics(metrics.New()))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	req := httptest.NewRequest("GET", "/brokers", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `kafka_admin_api_http_requests_total{method="GET",route="/brokers",status="200"} 1`)
+	assert.Contains(t, string(body), "kafka_admin_api_sse_consumers 0")
+}
+
+func TestConsumerGroupHealth(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListConsumerGroups", mock.Anything).Return([]model.ConsumerGroup{
+		{GroupID: "group-1", State: "Stable"},
+		{GroupID: "group-2", State: "Stable"},
+	}, nil)
+
+	evaluator := lag.NewEvaluator(3)
+	now := time.Now()
+	for i := int64(0); i < 3; i++ {
+		evaluator.Record("group-1", "Stable", []model.TopicPartition{
+			{Topic: "orders", Partition: 0, Offset: 100, Lag: 10 + i},
+		}, now)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := New(mockClient, logger, WithLagEvaluator(evaluator))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	req := httptest.NewRequest("GET", "/consumer-groups/group-1/health", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var health model.ConsumerGroupHealth
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	assert.Equal(t, model.LagStalled, health.Status)
+	assert.Equal(t, int64(12), health.TotalLag)
+	assert.Len(t, health.Partitions, 1)
+
+	req = httptest.NewRequest("GET", "/consumer-groups/group-2/health", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/consumer-groups", nil)
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+
+	var groups []model.ConsumerGroup
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&groups))
+	assert.Equal(t, model.LagStalled, groups[0].Health)
+	assert.Empty(t, groups[1].Health)
+}
+
+func TestConsumerGroupHealthDisabled(t *testing.T) {
+	app := setupTestApp(new(MockKafkaClient))
+
+	req := httptest.NewRequest("GET", "/consumer-groups/group-1/health", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+}
+
+func TestKafkaErrorMapping(t *testing.T) {
+	kafkaError := func(code kafka.ErrorCode, msg string) error {
+		return &kafkaclient.Error{Op: "op", Err: kafka.NewError(code, msg, false)}
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"already exists", kafkaError(kafka.ErrTopicAlreadyExists, "Topic 'orders' already exists."), 409, "TOPIC_ALREADY_EXISTS"},
+		{"invalid replication factor", kafkaError(kafka.ErrInvalidReplicationFactor, "Replication factor: 5 larger than available brokers: 3."), 400, "INVALID_REPLICATION_FACTOR"},
+		{"not authorized", kafkaError(kafka.ErrTopicAuthorizationFailed, "Topic authorization failed"), 403, "TOPIC_AUTHORIZATION_FAILED"},
+		{"timed out", kafkaError(kafka.ErrTimedOut, "Local: Timed out"), 504, "TIMED_OUT"},
+		{"unmapped code", kafkaError(kafka.ErrNotController, "Not controller"), 500, "NOT_CONTROLLER"},
+		{"context deadline", fmt.Errorf("create topic: %w", context.DeadlineExceeded), 504, "TIMED_OUT"},
+		{"plain error", errors.New("boom"), 500, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockKafkaClient)
+			mockClient.On("CreateTopic", mock.Anything, mock.Anything).Return(tt.err)
+			app := setupTestApp(mockClient)
+
+			body := `{"name": "orders", "partitions": 3, "replication_factor": 3}`
+			req := httptest.NewRequest("POST", "/topics", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+
+			var errBody map[string]string
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&errBody))
+			assert.Equal(t, tt.err.Error(), errBody["error"])
+			assert.Equal(t, tt.code, errBody["code"])
+			assert.NotEmpty(t, errBody["request_id"])
+		})
+	}
+}
+
+func TestGetTopicNotFound(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("GetTopic", mock.Anything, "missing").Return(nil, &kafkaclient.Error{
+		Err: kafka.NewError(kafka.ErrUnknownTopicOrPart, "topic missing not found", false),
+	})
+	app := setupTestApp(mockClient)
+
+	req := httptest.NewRequest("GET", "/topics/missing", nil)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	var errBody map[string]string
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&errBody))
+	assert.Equal(t, "UNKNOWN_TOPIC_OR_PART", errBody["code"])
+	assert.Equal(t, resp.Header.Get("X-Request-ID"), errBody["request_id"])
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	keysFile := "keys:\n" +
+		"  - name: reader\n    hash: " + auth.HashKey("reader-key") + "\n    scopes: [read-metadata]\n"
+	require.NoError(t, os.WriteFile(path, []byte(keysFile), 0o600))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	keys, err := auth.LoadKeyStore(path, logger)
+	require.NoError(t, err)
+
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListTopics", mock.Anything).Return([]model.Topic{}, nil)
+
+	h := New(mockClient, logger, WithAPIKeys(keys))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		status int
+	}{
+		{"health is public", "GET", "/health/live", "", 200},
+		{"missing key", "GET", "/topics", "", 401},
+		{"unknown key", "GET", "/topics", "nope", 401},
+		{"scope granted", "GET", "/topics", "reader-key", 200},
+		{"scope missing", "DELETE", "/topics/orders?confirm=orders", "reader-key", 403},
+		{"admin scope missing", "PUT", "/scram-users/app", "reader-key", 403},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set("X-API-Key", tt.key)
+			}
+			resp, err := app.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+		})
+	}
+	mockClient.AssertNotCalled(t, "DeleteTopic", mock.Anything, mock.Anything)
+}
+
+func TestTopicPolicyViolations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "topic-policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"min_partitions: 3\nreplication_factor_matches_brokers: true\nmax_retention_ms: 604800000\n"), 0o600))
+	policy, err := topicpolicy.Load(path)
+	require.NoError(t, err)
+
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListBrokers", mock.Anything).Return([]model.Broker{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+	mockClient.On("ListTopics", mock.Anything).Return([]model.Topic{}, nil)
+
+	h := New(mockClient, slog.New(slog.NewTextHandler(io.Discard, nil)), WithTopicPolicy(policy))
+	app := fiber.New(fiber.Config{ErrorHandler: h.ErrorHandler})
+	h.SetupRoutes(app)
+
+	send := func(method, path, body string) (int, map[string]any) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		var out map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+		return resp.StatusCode, out
+	}
+
+	status, body := send("POST", "/topics",
+		`{"name": "orders", "partitions": 1, "replication_factor": 2, "configs": {"retention.ms": "-1"}}`)
+	assert.Equal(t, 422, status)
+	assert.Equal(t, "TOPIC_POLICY_VIOLATION", body["code"])
+	assert.Len(t, body["violations"], 3)
+
+	status, body = send("PUT", "/topics/orders", `{"configs": {"retention.ms": "999999999999"}}`)
+	assert.Equal(t, 422, status)
+	assert.Len(t, body["violations"], 1)
+
+	// A rejected change of an apply is a client error, not a server fault.
+	status, body = send("POST", "/topics:apply",
+		`{"topics": [{"name": "orders", "partitions": 1, "replication_factor": 3, "configs": {"retention.ms": "86400000"}}]}`)
+	assert.Equal(t, 422, status)
+	results := body["results"].([]any)
+	require.Len(t, results, 1)
+	assert.Equal(t, model.ChangeFailed, results[0].(map[string]any)["status"])
+	assert.Equal(t, "TOPIC_POLICY_VIOLATION", results[0].(map[string]any)["code"])
+
+	mockClient.AssertNotCalled(t, "CreateTopic", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "UpdateTopicConfig", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPlanTopics(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListTopics", mock.Anything).Return([]model.Topic{
+		{Name: "orders", PartitionCount: 3, ReplicationFactor: 3},
+		{Name: "payments", PartitionCount: 6, ReplicationFactor: 2},
+		{Name: "legacy", PartitionCount: 1, ReplicationFactor: 3},
+	}, nil)
+	policy, retention := "delete", "604800000"
+	mockClient.On("DescribeTopicConfigs", mock.Anything, "orders").Return([]model.ConfigEntry{
+		{Name: "cleanup.policy", Value: &policy, Source: model.ConfigSourceDefault},
+		{Name: "retention.ms", Value: &retention, Source: model.ConfigSourceDynamicTopic},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `{
+		"prune": true,
+		"topics": [
+			{"name": "orders", "partitions": 6, "replication_factor": 3,
+			 "configs": {"retention.ms": "86400000", "cleanup.policy": "delete"}},
+			{"name": "payments", "partitions": 6, "replication_factor": 3},
+			{"name": "refunds", "partitions": 3, "replication_factor": 3}
+		]
+	}`
+	req := httptest.NewRequest("POST", "/topics:plan", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	var plan model.TopicPlan
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&plan))
+	require.Len(t, plan.Changes, 4)
+	assert.Equal(t, model.ChangeCreate, plan.Changes[0].Action)
+	assert.Equal(t, "refunds", plan.Changes[0].Topic)
+	assert.Equal(t, model.ChangeUpdateConfigs, plan.Changes[1].Action)
+	assert.Equal(t, map[string]model.ConfigChange{"retention.ms": {From: "604800000", To: "86400000"}}, plan.Changes[1].Configs)
+	assert.Equal(t, model.ChangeAddPartitions, plan.Changes[2].Action)
+	assert.Equal(t, &model.PartitionChange{From: 3, To: 6}, plan.Changes[2].Partitions)
+	assert.Equal(t, model.ChangeDelete, plan.Changes[3].Action)
+	assert.Equal(t, "legacy", plan.Changes[3].Topic)
+	require.Len(t, plan.Warnings, 1)
+	assert.Contains(t, plan.Warnings[0], "payments has replication factor 2")
+
+	mockClient.AssertNotCalled(t, "CreateTopic", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "DeleteTopic", mock.Anything, mock.Anything)
+}
+
+func TestApplyTopicsYAML(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListTopics", mock.Anything).Return([]model.Topic{
+		{Name: "orders", PartitionCount: 3, ReplicationFactor: 3},
+		{Name: "legacy", PartitionCount: 1, ReplicationFactor: 3},
+	}, nil)
+	mockClient.On("CreateTopic", mock.Anything, mock.MatchedBy(func(req model.CreateTopicRequest) bool {
+		return req.Name == "refunds" && req.Partitions == 3 && req.Configs["retention.ms"] == "3600000"
+	})).Return(errors.New("broker unavailable"))
+	mockClient.On("CreatePartitions", mock.Anything, "orders", int32(6), [][]int32(nil)).Return(nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `topics:
+  - name: orders
+    partitions: 6
+    replication_factor: 3
+  - name: refunds
+    partitions: 3
+    replication_factor: 3
+    configs:
+      retention.ms: "3600000"
+`
+	req := httptest.NewRequest("POST", "/topics:apply", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/yaml")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	var result model.TopicApplyResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "refunds", result.Results[0].Topic)
+	assert.Equal(t, model.ChangeFailed, result.Results[0].Status)
+	assert.Equal(t, "broker unavailable", result.Results[0].Error)
+	assert.Equal(t, "orders", result.Results[1].Topic)
+	assert.Equal(t, model.ChangeApplied, result.Results[1].Status)
+
+	mockClient.AssertNotCalled(t, "DeleteTopic", mock.Anything, mock.Anything)
+}
+
+func TestApplyTopicsPruneSkipsActiveTopics(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListTopics", mock.Anything).Return([]model.Topic{
+		{Name: "orders", PartitionCount: 3, ReplicationFactor: 3},
+		{Name: "legacy", PartitionCount: 1, ReplicationFactor: 3},
+		{Name: "scratch", PartitionCount: 1, ReplicationFactor: 3},
+	}, nil)
+	mockClient.On("ListConsumerGroups", mock.Anything).Return([]model.ConsumerGroup{
+		{GroupID: "reporting", State: "Stable"},
+	}, nil)
+	mockClient.On("DescribeConsumerGroups", mock.Anything, []string{"reporting"}).Return([]model.ConsumerGroupDetail{{
+		GroupID: "reporting",
+		State:   "Stable",
+		Members: []model.Member{{MemberID: "m1", Assignment: []model.TopicPartition{
+			{Topic: "legacy", Partition: 0},
+			{Topic: "scratch", Partition: 0},
+		}}},
+	}}, nil)
+	mockClient.On("DeleteTopic", mock.Anything, "scratch").Return(nil)
+
+	app := setupTestApp(mockClient)
+
+	body := `{
+		"prune": true,
+		"prune_force": ["scratch"],
+		"topics": [{"name": "orders", "partitions": 3, "replication_factor": 3}]
+	}`
+	req := httptest.NewRequest("POST", "/topics:apply", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var result model.TopicApplyResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "legacy", result.Results[0].Topic)
+	assert.Equal(t, model.ChangeSkipped, result.Results[0].Status)
+	assert.Contains(t, result.Results[0].Error, "active consumer groups reporting")
+	assert.Equal(t, "scratch", result.Results[1].Topic)
+	assert.True(t, result.Results[1].Force)
+	assert.Equal(t, model.ChangeApplied, result.Results[1].Status)
+
+	mockClient.AssertNotCalled(t, "DeleteTopic", mock.Anything, "legacy")
+}
+
+func TestPlanTopicsRejectsDuplicates(t *testing.T) {
+	app := setupTestApp(new(MockKafkaClient))
+
+	body := `{"topics": [
+		{"name": "orders", "partitions": 3, "replication_factor": 3},
+		{"name": "orders", "partitions": 6, "replication_factor": 3}
+	]}`
+	req := httptest.NewRequest("POST", "/topics:plan", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestGrantConsumerACLs(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("CreateACLs", mock.Anything, mock.Anything).Return(nil)
+
+	app := setupAdminApp(t, mockClient)
+
+	body := `{"role": "consumer", "principal": "User:billing", "topic": "orders", "group": "billing-svc"}`
+	req := httptest.NewRequest("POST", "/acls/grants", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 201, resp.StatusCode)
+
+	acls := mockClient.Calls[0].Arguments.Get(1).([]model.ACL)
+	type grant struct{ resourceType, name, operation string }
+	var grants []grant
+	for _, acl := range acls {
+		assert.Equal(t, "User:billing", acl.Principal)
+		assert.Equal(t, "ALLOW", acl.Permission)
+		grants = append(grants, grant{acl.ResourceType, acl.ResourceName, acl.Operation})
+	}
+	assert.ElementsMatch(t, []grant{
+		{"TOPIC", "orders", "READ"},
+		{"TOPIC", "orders", "DESCRIBE"},
+		{"GROUP", "billing-svc", "READ"},
+	}, grants)
+}
+
+func TestGrantConsumerRequiresGroup(t *testing.T) {
+	app := setupAdminApp(t, new(MockKafkaClient))
+
+	body := `{"role": "consumer", "principal": "User:billing", "topic": "orders"}`
+	req := httptest.NewRequest("POST", "/acls/grants", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestACLFilters(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListACLs", mock.Anything, model.ACLFilter{ResourceType: "TOPIC", PatternType: "PREFIXED", Principal: "User:ci"}).
+		Return([]model.ACL{{ResourceType: "TOPIC", ResourceName: "ci.", PatternType: "PREFIXED", Principal: "User:ci"}}, nil)
+	mockClient.On("DeleteACLs", mock.Anything, model.ACLFilter{Principal: "User:ci"}).
+		Return([]model.ACL{{ResourceType: "TOPIC", ResourceName: "ci.", Principal: "User:ci"}}, nil)
+
+	app := setupAdminApp(t, mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/acls?resource_type=TOPIC&pattern_type=PREFIXED&principal=User:ci", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/acls", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode, "an empty filter would delete every ACL")
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/acls?principal=User:ci", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestUpsertScramCredential(t *testing.T) {
+	var stored []byte
+	mockClient := new(MockKafkaClient)
+	mockClient.On("UpsertScramCredential", mock.Anything, "billing-svc", "SCRAM-SHA-512", 8192, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(4).([]byte) }).
+		Return(nil)
+
+	app := setupAdminApp(t, mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("PUT", "/scram-users/billing-svc", nil))
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "no-store", resp.Header.Get("Cache-Control"))
+
+	var secret model.ScramCredentialSecret
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&secret))
+	assert.Equal(t, "billing-svc", secret.User)
+	assert.Equal(t, "SCRAM-SHA-512", secret.Mechanism)
+	assert.Len(t, secret.Password, 43)
+	assert.Equal(t, secret.Password, string(stored))
+}
+
+func TestUpsertScramCredentialValidation(t *testing.T) {
+	app := setupAdminApp(t, new(MockKafkaClient))
+
+	req := httptest.NewRequest("PUT", "/scram-users/billing-svc", strings.NewReader(`{"iterations": 100}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestDeleteScramCredentialsDefaultsToAllMechanisms(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("DescribeScramUsers", mock.Anything, []string{"billing-svc"}).Return([]model.ScramUser{{
+		User: "billing-svc",
+		Credentials: []model.ScramCredential{
+			{Mechanism: "SCRAM-SHA-256", Iterations: 4096},
+			{Mechanism: "SCRAM-SHA-512", Iterations: 8192},
+		},
+	}}, nil)
+	mockClient.On("DeleteScramCredentials", mock.Anything, "billing-svc", []string{"SCRAM-SHA-256", "SCRAM-SHA-512"}).Return(nil)
+	mockClient.On("DeleteScramCredentials", mock.Anything, "billing-svc", []string{"SCRAM-SHA-256"}).Return(nil)
+
+	app := setupAdminApp(t, mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/scram-users/billing-svc", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/scram-users/billing-svc?mechanism=SCRAM-SHA-256", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	mockClient.AssertNumberOfCalls(t, "DescribeScramUsers", 1)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSetQuotas(t *testing.T) {
+	entity := model.QuotaEntity{User: "billing-svc", ClientID: model.QuotaDefault}
+	mockClient := new(MockKafkaClient)
+	mockClient.On("AlterClientQuotas", mock.Anything, entity,
+		map[string]float64{"producer_byte_rate": 1048576}, []string(nil)).Return(nil)
+
+	app := setupAdminApp(t, mockClient)
+
+	send := func(body string) int {
+		req := httptest.NewRequest("PUT", "/quotas", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 200, send(`{"entity": {"user": "billing-svc", "client_id": "<default>"}, "quotas": {"producer_byte_rate": 1048576}}`))
+	assert.Equal(t, 400, send(`{"entity": {"user": "billing-svc"}, "quotas": {"fetch_rate": 10}}`))
+	assert.Equal(t, 400, send(`{"entity": {"user": "billing-svc"}, "quotas": {"consumer_byte_rate": -1}}`))
+	assert.Equal(t, 400, send(`{"entity": {}, "quotas": {"consumer_byte_rate": 1024}}`))
+
+	mockClient.AssertNumberOfCalls(t, "AlterClientQuotas", 1)
+}
+
+func TestDeleteQuotas(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("AlterClientQuotas", mock.Anything, model.QuotaEntity{ClientID: "batch"},
+		map[string]float64(nil), []string{"request_percentage"}).Return(nil)
+	mockClient.On("AlterClientQuotas", mock.Anything, model.QuotaEntity{User: model.QuotaDefault},
+		map[string]float64(nil), model.QuotaKeys).
+		Return(&kafkaclient.Error{
+			Op:  "alter client quotas",
+			Err: kafka.NewError(kafka.ErrClusterAuthorizationFailed, "Cluster authorization failed.", false),
+		})
+
+	app := setupAdminApp(t, mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/quotas?client_id=batch&key=request_percentage", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/quotas?user=%3Cdefault%3E", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 403, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/quotas?key=request_percentage", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBrokerConfigs(t *testing.T) {
+	value := "168"
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListBrokers", mock.Anything).Return([]model.Broker{{ID: 1}, {ID: 2}}, nil)
+	mockClient.On("DescribeBrokerConfigs", mock.Anything, "2").Return([]model.ConfigEntry{
+		{Name: "log.retention.hours", Value: &value, Source: model.ConfigSourceStatic, ReadOnly: true},
+		{Name: "ssl.keystore.password", Source: model.ConfigSourceDynamicBroker, Sensitive: true},
+	}, nil)
+
+	app := setupTestApp(mockClient)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/brokers/2/configs", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var entries []map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+	require.Len(t, entries, 2)
+	assert.Equal(t, "168", entries[0]["value"])
+	assert.Equal(t, "static", entries[0]["source"])
+	assert.Nil(t, entries[1]["value"])
+	assert.Equal(t, true, entries[1]["sensitive"])
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/brokers/7/configs", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 404, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/brokers/one/configs", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+}
+
+func TestUpdateBrokerConfigsRejectsInvalidKeys(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListBrokers", mock.Anything).Return([]model.Broker{{ID: 1}}, nil)
+	mockClient.On("DescribeBrokerConfigs", mock.Anything, "1").Return([]model.ConfigEntry{
+		{Name: "log.retention.hours", Source: model.ConfigSourceStatic, ReadOnly: true},
+		{Name: "log.cleaner.threads", Source: model.ConfigSourceDefault},
+	}, nil)
+
+	app := setupAdminApp(t, mockClient)
+
+	body := `{"configs": {"log.retention.hours": "24", "no.such.key": "1", "log.cleaner.threads": "2"}}`
+	req := httptest.NewRequest("PUT", "/brokers/1/configs", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 400, resp.StatusCode)
+
+	var result map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Contains(t, result["error"], "log.retention.hours is not dynamically updatable")
+	assert.Contains(t, result["error"], "unknown config no.such.key")
+	mockClient.AssertNotCalled(t, "AlterBrokerConfigs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateClusterConfigs(t *testing.T) {
+	current := "1"
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListBrokers", mock.Anything).Return([]model.Broker{{ID: 3}, {ID: 2}}, nil)
+	mockClient.On("DescribeBrokerConfigs", mock.Anything, "2").Return([]model.ConfigEntry{
+		{Name: "log.cleaner.threads", Source: model.ConfigSourceDefault},
+	}, nil)
+	mockClient.On("DescribeBrokerConfigs", mock.Anything, "").Return([]model.ConfigEntry{
+		{Name: "log.cleaner.threads", Value: &current, Source: model.ConfigSourceDynamicDefault},
+	}, nil)
+	mockClient.On("AlterBrokerConfigs", mock.Anything, "", map[string]string{"log.cleaner.threads": "2"}).Return(nil)
+
+	app := setupAdminApp(t, mockClient)
+
+	req := httptest.NewRequest("PUT", "/cluster/configs", strings.NewReader(`{"configs": {"log.cleaner.threads": "2"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	mockClient.AssertExpectations(t)
+}
+
+func TestPolicyAuthorization(t *testing.T) {
+	dir := t.TempDir()
+	keysPath := filepath.Join(dir, "keys.yaml")
+	keysFile := "keys:\n" +
+		"  - name: payments\n    hash: " + auth.HashKey("payments-key") +
+		"\n    scopes: [read-metadata, manage-topics, manage-groups]\n"
+	require.NoError(t, os.WriteFile(keysPath, []byte(keysFile), 0o600))
+	policyPath := filepath.Join(dir, "policy.yaml")
+	policyFile := "rules:\n" +
+		"  - principals: [\"key:payments\"]\n    resource: topic\n    name: payments.\n    pattern_type: prefix\n    actions: [describe, create, consume]\n" +
+		"  - principals: [\"key:payments\"]\n    resource: group\n    name: payments-\n    pattern_type: prefix\n    actions: [describe, reset-offsets]\n"
+	require.NoError(t, os.WriteFile(policyPath, []byte(policyFile), 0o600))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	keys, err := auth.LoadKeyStore(keysPath, logger)
+	require.NoError(t, err)
+	policy, err := auth.LoadPolicy(policyPath)
+	require.NoError(t, err)
+
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListTopics", mock.Anything).Return([]model.Topic{
+		{Name: "payments.orders"}, {Name: "billing.invoices"},
+	}, nil)
+	mockClient.On("ListConsumerGroups", mock.Anything).Return([]model.ConsumerGroup{
+		{GroupID: "payments-svc"}, {GroupID: "billing-svc"},
+	}, nil)
+	mockClient.On("CreateTopic", mock.Anything, mock.Anything).Return(nil)
+	mockClient.On("GetConsumerGroup", mock.Anything, "payments-svc").Return(&model.ConsumerGroupDetail{
+		GroupID: "payments-svc",
+		State:   "Empty",
+		Topics:  []model.TopicLag{{Topic: "payments.orders"}, {Topic: "billing.invoices"}},
+	}, nil)
+	mockClient.On("ResetConsumerGroupOffsets", mock.Anything, "payments-svc", mock.Anything).Return([]model.OffsetReset{}, nil)
+
+	h := New(mockClient, logger, WithAPIKeys(keys), WithPolicy(policy))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	do := func(method, path, body string) *http.Response {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "payments-key")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := do("GET", "/topics", "")
+	var topics []model.Topic
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&topics))
+	require.Len(t, topics, 1)
+	assert.Equal(t, "payments.orders", topics[0].Name)
+
+	resp = do("GET", "/consumer-groups", "")
+	var groups []model.ConsumerGroup
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&groups))
+	require.Len(t, groups, 1)
+	assert.Equal(t, "payments-svc", groups[0].GroupID)
+
+	resp = do("POST", "/topics", `{"name": "billing.refunds", "partitions": 1, "replication_factor": 1}`)
+	assert.Equal(t, 403, resp.StatusCode)
+	resp = do("POST", "/topics", `{"name": "payments.refunds", "partitions": 1, "replication_factor": 1}`)
+	assert.Equal(t, 201, resp.StatusCode)
+	resp = do("DELETE", "/topics/payments.orders?confirm=payments.orders", "")
+	assert.Equal(t, 403, resp.StatusCode)
+	resp = do("DELETE", "/consumer-groups/payments-svc", "")
+	assert.Equal(t, 403, resp.StatusCode)
+
+	// Offsets can only be moved on topics the caller may consume.
+	resp = do("POST", "/consumer-groups/payments-svc/offsets/reset", `{"strategy": "to-earliest", "topic": "billing.invoices"}`)
+	assert.Equal(t, 403, resp.StatusCode)
+	resp = do("POST", "/consumer-groups/payments-svc/offsets/reset", `{"strategy": "to-earliest"}`)
+	assert.Equal(t, 403, resp.StatusCode)
+	resp = do("DELETE", "/consumer-groups/payments-svc/offsets?topic=billing.invoices", "")
+	assert.Equal(t, 403, resp.StatusCode)
+
+	resp = do("POST", "/consumer-groups/payments-svc/offsets/reset", `{"strategy": "to-earliest", "topic": "payments.orders", "dry_run": true}`)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	// A listed topic the caller cannot see is reported, not planned as a create.
+	resp = do("POST", "/topics:plan", `{"prune": true, "topics": [
+		{"name": "billing.invoices", "partitions": 1, "replication_factor": 1},
+		{"name": "payments.orders", "partitions": 1, "replication_factor": 1},
+		{"name": "payments.new", "partitions": 1, "replication_factor": 1}
+	]}`)
+	require.Equal(t, 200, resp.StatusCode)
+	var plan model.TopicPlan
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&plan))
+	assert.Equal(t, []string{"billing.invoices"}, plan.Forbidden)
+	require.Len(t, plan.Changes, 2)
+	assert.Equal(t, model.ChangeCreate, plan.Changes[0].Action)
+	assert.Equal(t, "payments.new", plan.Changes[0].Topic)
+	assert.Equal(t, model.ChangeAddPartitions, plan.Changes[1].Action)
+
+	mockClient.AssertNumberOfCalls(t, "CreateTopic", 1)
+	mockClient.AssertNumberOfCalls(t, "ResetConsumerGroupOffsets", 1)
+	mockClient.AssertNotCalled(t, "DeleteConsumerGroupOffsets", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "DeleteTopic", mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "DeleteConsumerGroups", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTopicAudited(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	auditLog, err := audit.Open(audit.Config{Path: filepath.Join(t.TempDir(), "audit.jsonl")}, logger)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	// retention.ms is at its default, which the topic's own configs omit.
+	retention, cleanup := "604800000", "delete"
+	mockClient := new(MockKafkaClient)
+	mockClient.On("DescribeTopicConfigs", mock.Anything, "orders").Return([]model.ConfigEntry{
+		{Name: "retention.ms", Value: &retention, Source: model.ConfigSourceDefault},
+		{Name: "cleanup.policy", Value: &cleanup, Source: model.ConfigSourceDynamicTopic},
+	}, nil)
+	mockClient.On("UpdateTopicConfig", mock.Anything, "orders", mock.Anything).Return(nil)
+
+	h := New(mockClient, logger, WithAudit(auditLog))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	req := httptest.NewRequest("PUT", "/topics/orders", strings.NewReader(`{"configs": {"retention.ms": "86400000"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/audit?resource=orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var events []struct {
+		Action    string `json:"action"`
+		RequestID string `json:"request_id"`
+		Outcome   string `json:"outcome"`
+		Before    struct {
+			Configs map[string]string `json:"configs"`
+		} `json:"before"`
+		After struct {
+			Configs map[string]string `json:"configs"`
+		} `json:"after"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "alter", events[0].Action)
+	assert.Equal(t, model.AuditSuccess, events[0].Outcome)
+	assert.NotEmpty(t, events[0].RequestID)
+	assert.Equal(t, map[string]string{"retention.ms": "604800000"}, events[0].Before.Configs)
+	assert.Equal(t, map[string]string{"retention.ms": "86400000"}, events[0].After.Configs)
+}
+
+func TestRejectedAttemptsAudited(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	keysFile := "keys:\n" +
+		"  - name: reader\n    hash: " + auth.HashKey("reader-key") + "\n    scopes: [read-metadata, read-audit]\n" +
+		"  - name: ops\n    hash: " + auth.HashKey("ops-key") + "\n    scopes: [manage-topics]\n"
+	require.NoError(t, os.WriteFile(path, []byte(keysFile), 0o600))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	keys, err := auth.LoadKeyStore(path, logger)
+	require.NoError(t, err)
+	auditLog, err := audit.Open(audit.Config{Path: filepath.Join(t.TempDir(), "audit.jsonl")}, logger)
+	require.NoError(t, err)
+	defer auditLog.Close()
+
+	mockClient := new(MockKafkaClient)
+	h := New(mockClient, logger, WithAPIKeys(keys), WithAudit(auditLog))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	send := func(method, path, key, body string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(apiKeyHeader, key)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+	assert.Equal(t, 403, send("PUT", "/topics/orders", "reader-key", `{"configs": {"retention.ms": "1"}}`))
+	assert.Equal(t, 400, send("POST", "/topics", "ops-key", `{"name": "payments", "partitions": 0}`))
+
+	req := httptest.NewRequest("GET", "/audit", nil)
+	req.Header.Set(apiKeyHeader, "reader-key")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, 200, resp.StatusCode)
+
+	var events []model.AuditEvent
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+	require.Len(t, events, 2)
+
+	created, altered := events[0], events[1]
+	assert.Equal(t, "create", created.Action)
+	assert.Equal(t, "payments", created.Resource, "named from the request body")
+	assert.Equal(t, "key:ops", created.Principal)
+	assert.Equal(t, model.AuditFailure, created.Outcome)
+
+	assert.Equal(t, "alter", altered.Action)
+	assert.Equal(t, "orders", altered.Resource)
+	assert.Equal(t, "key:reader", altered.Principal)
+	assert.Equal(t, model.AuditFailure, altered.Outcome)
+	assert.Contains(t, altered.Error, "missing scope manage-topics")
+	mockClient.AssertNotCalled(t, "UpdateTopicConfig", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminRoutesRefusedWithoutAuthentication(t *testing.T) {
+	mockClient := new(MockKafkaClient)
+	app := setupTestApp(mockClient)
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/acls"},
+		{"POST", "/acls"},
+		{"DELETE", "/acls?principal=User:ci"},
+		{"POST", "/acls/grants"},
+		{"GET", "/scram-users"},
+		{"PUT", "/scram-users/app"},
+		{"DELETE", "/scram-users/app"},
+		{"PUT", "/quotas"},
+		{"DELETE", "/quotas?user=app"},
+		{"PUT", "/brokers/1/configs"},
+		{"PUT", "/cluster/configs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{}`)))
+			require.NoError(t, err)
+			assert.Equal(t, 403, resp.StatusCode)
+		})
+	}
+	assert.Empty(t, mockClient.Calls, "no admin call reaches Kafka")
+}
+
+func TestBearerTokenAuthentication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwks := fmt.Sprintf(`{"keys": [{"kty": "RSA", "kid": "k1", "n": %q, "e": "AQAB"}]}`,
+		base64.RawURLEncoding.EncodeToString(key.N.Bytes()))
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, []byte(jwks), 0o600))
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	verifier, err := auth.NewJWTVerifier(context.Background(), auth.JWTConfig{
+		Issuer:      "https://sso.example.com",
+		Audience:    "kafka-admin-api",
+		JWKSFile:    path,
+		GroupScopes: map[string][]auth.Scope{"ops": {auth.ScopeReadMetadata}},
+	}, logger)
+	require.NoError(t, err)
+
+	mockClient := new(MockKafkaClient)
+	mockClient.On("ListBrokers", mock.Anything).Return([]model.Broker{}, nil)
+
+	h := New(mockClient, logger, WithJWT(verifier))
+	app := fiber.New()
+	h.SetupRoutes(app)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":    "https://sso.example.com",
+		"aud":    "kafka-admin-api",
+		"sub":    "bob",
+		"groups": []string{"ops"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "k1"
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/brokers", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/topics", nil)
+	req.Header.Set("Authorization", "Bearer "+signed+"x")
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 401, resp.StatusCode)
+}
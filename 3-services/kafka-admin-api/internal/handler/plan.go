@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gopkg.in/yaml.v3"
+
+	"kafka-admin-api/internal/auth"
+	"kafka-admin-api/internal/model"
+)
+
+// planTopics diffs the desired topics document against the cluster.
+func (h *Handler) planTopics(c *fiber.Ctx) error {
+	plan, err := h.topicPlan(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(plan)
+}
+
+// applyTopics plans like planTopics and then executes every change in
+// order. Applying the same document again is a no-op. A failed change does
+// not stop the rest; each one reports its own status, and the response
+// takes the highest status of the failures, mapped like any handler error.
+func (h *Handler) applyTopics(c *fiber.Ctx) error {
+	plan, err := h.topicPlan(c)
+	if err != nil {
+		return err
+	}
+
+	resp := model.TopicApplyResponse{
+		Results:   make([]model.TopicChangeResult, 0, len(plan.Changes)),
+		Warnings:  plan.Warnings,
+		Forbidden: plan.Forbidden,
+	}
+	status := fiber.StatusOK
+	for _, change := range plan.Changes {
+		result := model.TopicChangeResult{TopicChange: change, Status: model.ChangeApplied}
+		var inUse *topicInUseError
+		if err := h.applyTopicChange(c, change); errors.As(err, &inUse) {
+			h.logger.Warn("topic change skipped", "topic", change.Topic, "action", change.Action, "error", err)
+			result.Status = model.ChangeSkipped
+			result.Error = err.Error()
+		} else if err != nil {
+			h.logger.Error("apply topic change failed", "topic", change.Topic, "action", change.Action, "error", err)
+			changeStatus, code := errorStatus(err)
+			result.Status = model.ChangeFailed
+			result.Error = err.Error()
+			result.Code = code
+			status = max(status, changeStatus)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return c.Status(status).JSON(resp)
+}
+
+func (h *Handler) applyTopicChange(c *fiber.Ctx, change model.TopicChange) error {
+	ctx := c.Context()
+	// reject records a change the policy refused before it reached Kafka.
+	reject := func(action auth.Action, err error) error {
+		h.recordAudit(c, auth.ResourceTopic, change.Topic, action, nil, nil, err)
+		return err
+	}
+	switch change.Action {
+	case model.ChangeCreate:
+		if err := h.authorize(c, auth.ResourceTopic, change.Topic, auth.ActionCreate); err != nil {
+			return reject(auth.ActionCreate, err)
+		}
+		req := model.CreateTopicRequest{
+			Name:              change.Topic,
+			Partitions:        int32(change.Partitions.To),
+			ReplicationFactor: change.ReplicationFactor,
+			Configs:           configTargets(change.Configs),
+		}
+		if err := h.checkCreatePolicy(ctx, req); err != nil {
+			return reject(auth.ActionCreate, err)
+		}
+		err := h.client.CreateTopic(ctx, req)
+		h.recordAudit(c, auth.ResourceTopic, change.Topic, auth.ActionCreate, nil, req, err)
+		return err
+
+	case model.ChangeUpdateConfigs:
+		if err := h.authorize(c, auth.ResourceTopic, change.Topic, auth.ActionAlter); err != nil {
+			return reject(auth.ActionAlter, err)
+		}
+		before := make(map[string]string, len(change.Configs))
+		for k, v := range change.Configs {
+			before[k] = v.From
+		}
+		after := configTargets(change.Configs)
+		if err := h.topics.CheckConfigs(after); err != nil {
+			return reject(auth.ActionAlter, err)
+		}
+		err := h.client.UpdateTopicConfig(ctx, change.Topic, after)
+		h.recordAudit(c, auth.ResourceTopic, change.Topic, auth.ActionAlter,
+			fiber.Map{"configs": before}, fiber.Map{"configs": after}, err)
+		return err
+
+	case model.ChangeAddPartitions:
+		if err := h.authorize(c, auth.ResourceTopic, change.Topic, auth.ActionAlter); err != nil {
+			return reject(auth.ActionAlter, err)
+		}
+		if err := h.topics.CheckPartitions(int32(change.Partitions.To)); err != nil {
+			return reject(auth.ActionAlter, err)
+		}
+		err := h.client.CreatePartitions(ctx, change.Topic, int32(change.Partitions.To), nil)
+		h.recordAudit(c, auth.ResourceTopic, change.Topic, auth.ActionAlter,
+			fiber.Map{"partitions": change.Partitions.From}, fiber.Map{"partitions": change.Partitions.To}, err)
+		return err
+
+	case model.ChangeDelete:
+		if err := h.authorize(c, auth.ResourceTopic, change.Topic, auth.ActionDelete); err != nil {
+			return reject(auth.ActionDelete, err)
+		}
+		if !change.Force {
+			groups, err := h.activeConsumerGroups(ctx, change.Topic)
+			if err != nil {
+				return err
+			}
+			if len(groups) > 0 {
+				return reject(auth.ActionDelete, &topicInUseError{topic: change.Topic, groups: groups})
+			}
+		}
+		err := h.client.DeleteTopic(ctx, change.Topic)
+		h.recordAudit(c, auth.ResourceTopic, change.Topic, auth.ActionDelete,
+			fiber.Map{"partitions": change.Partitions.From}, nil, err)
+		return err
+	}
+	return fmt.Errorf("unknown change %q", change.Action)
+}
+
+// topicInUseError refuses to prune a topic that consumer groups still read.
+type topicInUseError struct {
+	topic  string
+	groups []string
+}
+
+func (e *topicInUseError) Error() string {
+	return fmt.Sprintf("topic %s has active consumer groups %s, list it in prune_force to delete anyway",
+		e.topic, strings.Join(e.groups, ", "))
+}
+
+// topicPlan parses the request document and diffs it against the cluster.
+func (h *Handler) topicPlan(c *fiber.Ctx) (*model.TopicPlan, error) {
+	doc, err := h.parseTopicsDocument(c)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := h.client.ListTopics(c.Context())
+	if err != nil {
+		h.logger.Error("list topics failed", "error", err)
+		return nil, err
+	}
+	existing := make(map[string]model.Topic, len(topics))
+	for _, t := range topics {
+		existing[t.Name] = t
+	}
+	visible := func(name string) bool {
+		return h.visible(c, auth.ResourceTopic, name)
+	}
+
+	plan, err := diffTopics(c.Context(), h.client, doc, existing, visible)
+	if err != nil {
+		h.logger.Error("plan topics failed", "error", err)
+		return nil, err
+	}
+	return plan, nil
+}
+
+// parseTopicsDocument reads a JSON body, or YAML when the content type says
+// so, so the file kept in git can be posted as is.
+func (h *Handler) parseTopicsDocument(c *fiber.Ctx) (*model.TopicsDocument, error) {
+	var doc model.TopicsDocument
+	if strings.Contains(c.Get(fiber.HeaderContentType), "yaml") {
+		if err := yaml.Unmarshal(c.Body(), &doc); err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid YAML document: %v", err))
+		}
+	} else if err := c.BodyParser(&doc); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validate.Struct(doc); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	seen := make(map[string]bool, len(doc.Topics))
+	for _, t := range doc.Topics {
+		if seen[t.Name] {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("topic %s is listed more than once", t.Name))
+		}
+		seen[t.Name] = true
+	}
+	return &doc, nil
+}
+
+// diffTopics returns the changes turning existing into doc: creates first,
+// then config updates, partition increases and, with Prune, deletes.
+// Configs are compared with their effective values, so listing a default
+// is not a change. Topics are described one at a time as needed. Existing
+// topics the caller cannot see are never changed: listed ones are reported
+// as forbidden and unlisted ones are not pruned.
+func diffTopics(ctx context.Context, client KafkaClient, doc *model.TopicsDocument, existing map[string]model.Topic, visible func(string) bool) (*model.TopicPlan, error) {
+	var creates, updates, increases, deletes []model.TopicChange
+	plan := &model.TopicPlan{}
+
+	desired := make(map[string]bool, len(doc.Topics))
+	for _, spec := range doc.Topics {
+		desired[spec.Name] = true
+
+		current, ok := existing[spec.Name]
+		if ok && !visible(spec.Name) {
+			plan.Forbidden = append(plan.Forbidden, spec.Name)
+			continue
+		}
+		if !ok {
+			change := model.TopicChange{
+				Action:            model.ChangeCreate,
+				Topic:             spec.Name,
+				Partitions:        &model.PartitionChange{To: int(spec.Partitions)},
+				ReplicationFactor: spec.ReplicationFactor,
+			}
+			if len(spec.Configs) > 0 {
+				change.Configs = make(map[string]model.ConfigChange, len(spec.Configs))
+				for k, v := range spec.Configs {
+					change.Configs[k] = model.ConfigChange{To: v}
+				}
+			}
+			creates = append(creates, change)
+			continue
+		}
+
+		if int(spec.ReplicationFactor) != current.ReplicationFactor {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"topic %s has replication factor %d, changing it to %d requires a partition reassignment",
+				spec.Name, current.ReplicationFactor, spec.ReplicationFactor))
+		}
+
+		switch {
+		case int(spec.Partitions) > current.PartitionCount:
+			increases = append(increases, model.TopicChange{
+				Action:     model.ChangeAddPartitions,
+				Topic:      spec.Name,
+				Partitions: &model.PartitionChange{From: current.PartitionCount, To: int(spec.Partitions)},
+			})
+		case int(spec.Partitions) < current.PartitionCount:
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+				"topic %s has %d partitions, the partition count cannot be decreased to %d",
+				spec.Name, current.PartitionCount, spec.Partitions))
+		}
+
+		if len(spec.Configs) == 0 {
+			continue
+		}
+		entries, err := client.DescribeTopicConfigs(ctx, spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		effective := make(map[string]*string, len(entries))
+		for _, e := range entries {
+			effective[e.Name] = e.Value
+		}
+		configs := make(map[string]model.ConfigChange)
+		for k, v := range spec.Configs {
+			// Sensitive values are never returned, so they always differ.
+			current := effective[k]
+			if current == nil || *current != v {
+				var from string
+				if current != nil {
+					from = *current
+				}
+				configs[k] = model.ConfigChange{From: from, To: v}
+			}
+		}
+		if len(configs) > 0 {
+			updates = append(updates, model.TopicChange{
+				Action:  model.ChangeUpdateConfigs,
+				Topic:   spec.Name,
+				Configs: configs,
+			})
+		}
+	}
+
+	if doc.Prune {
+		for name, t := range existing {
+			if !desired[name] && visible(name) {
+				deletes = append(deletes, model.TopicChange{
+					Action:     model.ChangeDelete,
+					Topic:      name,
+					Partitions: &model.PartitionChange{From: t.PartitionCount},
+					Force:      slices.Contains(doc.PruneForce, name),
+				})
+			}
+		}
+	}
+
+	plan.Changes = make([]model.TopicChange, 0, len(creates)+len(updates)+len(increases)+len(deletes))
+	for _, changes := range [][]model.TopicChange{creates, updates, increases, deletes} {
+		sort.Slice(changes, func(i, j int) bool { return changes[i].Topic < changes[j].Topic })
+		plan.Changes = append(plan.Changes, changes...)
+	}
+	return plan, nil
+}
+
+func configTargets(changes map[string]model.ConfigChange) map[string]string {
+	if len(changes) == 0 {
+		return nil
+	}
+	configs := make(map[string]string, len(changes))
+	for k, v := range changes {
+		configs[k] = v.To
+	}
+	return configs
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"kafka-admin-api/internal/auth"
+	"kafka-admin-api/internal/model"
+)
+
+// listQuotas describes client quotas. The user and client_id query
+// parameters filter by entity; either may be <default>.
+func (h *Handler) listQuotas(c *fiber.Ctx) error {
+	var filter model.QuotaEntity
+	if err := c.QueryParser(&filter); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	quotas, err := h.client.DescribeClientQuotas(c.Context(), filter)
+	if err != nil {
+		h.logger.Error("describe client quotas failed", "error", err)
+		return err
+	}
+	return c.JSON(quotas)
+}
+
+// setQuotas sets the given quotas of an entity; quotas not listed are
+// left unchanged.
+func (h *Handler) setQuotas(c *fiber.Ctx) error {
+	var req model.SetQuotasRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+	auditResource(c, req.Entity.String())
+
+	if err := h.validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if req.Entity.User == "" && req.Entity.ClientID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "entity user or client_id required")
+	}
+
+	err := h.client.AlterClientQuotas(c.Context(), req.Entity, req.Quotas, nil)
+	h.recordAudit(c, auth.ResourceQuota, req.Entity.String(), auth.ActionAlter, nil, req.Quotas, err)
+	if err != nil {
+		h.logger.Error("alter client quotas failed", "entity", req.Entity.String(), "error", err)
+		return err
+	}
+	return c.JSON(model.ClientQuota{Entity: req.Entity, Quotas: req.Quotas})
+}
+
+// deleteQuotas removes the quotas named by the key query parameter, a comma
+// separated list, or all of them, from the entity given by user and
+// client_id.
+func (h *Handler) deleteQuotas(c *fiber.Ctx) error {
+	var entity model.QuotaEntity
+	if err := c.QueryParser(&entity); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+	auditResource(c, entity.String())
+	if entity.User == "" && entity.ClientID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "user or client_id query parameter required")
+	}
+
+	keys := model.QuotaKeys
+	if k := c.Query("key"); k != "" {
+		keys = strings.Split(k, ",")
+		for _, key := range keys {
+			if !slices.Contains(model.QuotaKeys, key) {
+				return fiber.NewError(fiber.StatusBadRequest,
+					fmt.Sprintf("unknown quota %q, expected one of %s", key, strings.Join(model.QuotaKeys, ", ")))
+			}
+		}
+	}
+
+	err := h.client.AlterClientQuotas(c.Context(), entity, nil, keys)
+	h.recordAudit(c, auth.ResourceQuota, entity.String(), auth.ActionDelete, fiber.Map{"quotas": keys}, nil, err)
+	if err != nil {
+		h.logger.Error("remove client quotas failed", "entity", entity.String(), "error", err)
+		return err
+	}
+	return c.JSON(fiber.Map{"entity": entity, "removed": keys})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/gofiber/fiber/v2"
+
+	"kafka-admin-api/internal/auth"
+	"kafka-admin-api/internal/model"
+)
+
+const (
+	defaultScramMechanism  = "SCRAM-SHA-512"
+	defaultScramIterations = 8192
+	// passwordBytes of randomness give 256-bit generated passwords.
+	passwordBytes = 32
+)
+
+func (h *Handler) listScramUsers(c *fiber.Ctx) error {
+	users, err := h.client.DescribeScramUsers(c.Context(), nil)
+	if err != nil {
+		h.logger.Error("describe scram users failed", "error", err)
+		return err
+	}
+	return c.JSON(users)
+}
+
+func (h *Handler) getScramUser(c *fiber.Ctx) error {
+	name := c.Params("user")
+	users, err := h.client.DescribeScramUsers(c.Context(), []string{name})
+	if err != nil {
+		h.logger.Error("describe scram user failed", "user", name, "error", err)
+		return err
+	}
+	if len(users) == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "user "+name+" not found")
+	}
+	return c.JSON(users[0])
+}
+
+// upsertScramCredential creates or rotates the user's credential with a
+// generated password. The password is only ever returned in this response.
+func (h *Handler) upsertScramCredential(c *fiber.Ctx) error {
+	name := c.Params("user")
+
+	var req model.UpsertScramCredentialRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+		}
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if req.Mechanism == "" {
+		req.Mechanism = defaultScramMechanism
+	}
+	if req.Iterations == 0 {
+		req.Iterations = defaultScramIterations
+	}
+
+	secret := make([]byte, passwordBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	password := base64.RawURLEncoding.EncodeToString(secret)
+
+	err := h.client.UpsertScramCredential(c.Context(), name, req.Mechanism, req.Iterations, []byte(password))
+	h.recordAudit(c, auth.ResourceUser, name, auth.ActionAlter, nil, req, err)
+	if err != nil {
+		h.logger.Error("upsert scram credential failed", "user", name, "error", err)
+		return err
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(model.ScramCredentialSecret{
+		User:       name,
+		Mechanism:  req.Mechanism,
+		Iterations: req.Iterations,
+		Password:   password,
+	})
+}
+
+// deleteScramCredentials deletes the credential for the mechanism query
+// parameter, or all of the user's credentials without it.
+func (h *Handler) deleteScramCredentials(c *fiber.Ctx) error {
+	name := c.Params("user")
+
+	var mechanisms []string
+	if m := c.Query("mechanism"); m != "" {
+		mechanisms = []string{m}
+	} else {
+		users, err := h.client.DescribeScramUsers(c.Context(), []string{name})
+		if err != nil {
+			h.logger.Error("describe scram user failed", "user", name, "error", err)
+			return err
+		}
+		for _, u := range users {
+			for _, cred := range u.Credentials {
+				mechanisms = append(mechanisms, cred.Mechanism)
+			}
+		}
+		if len(mechanisms) == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "user "+name+" has no SCRAM credentials")
+		}
+	}
+
+	err := h.client.DeleteScramCredentials(c.Context(), name, mechanisms)
+	h.recordAudit(c, auth.ResourceUser, name, auth.ActionDelete, fiber.Map{"mechanisms": mechanisms}, nil, err)
+	if err != nil {
+		h.logger.Error("delete scram credentials failed", "user", name, "error", err)
+		return err
+	}
+	return c.JSON(fiber.Map{"user": name, "deleted": mechanisms})
+}
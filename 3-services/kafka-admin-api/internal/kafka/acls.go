@@ -0,0 +1,161 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"kafka-admin-api/internal/model"
+)
+
+// ListACLs returns the ACLs matching filter.
+func (c *Client) ListACLs(ctx context.Context, filter model.ACLFilter) (_ []model.ACL, err error) {
+	defer c.observe("ListACLs", time.Now(), &err)
+
+	binding, err := filterBinding(filter)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.admin.DescribeACLs(ctx, binding)
+	if err != nil {
+		return nil, wrapError("describe acls", err)
+	}
+	if result.Error.Code() != kafka.ErrNoError {
+		return nil, newError("describe acls", result.Error)
+	}
+	return fromBindings(result.ACLBindings), nil
+}
+
+// CreateACLs creates acls. Bindings are created independently, so on error
+// some of them may exist.
+func (c *Client) CreateACLs(ctx context.Context, acls []model.ACL) (err error) {
+	defer c.observe("CreateACLs", time.Now(), &err)
+
+	bindings := make(kafka.ACLBindings, 0, len(acls))
+	for _, acl := range acls {
+		binding, err := aclBinding(acl)
+		if err != nil {
+			return err
+		}
+		bindings = append(bindings, binding)
+	}
+
+	results, err := c.admin.CreateACLs(ctx, bindings)
+	if err != nil {
+		return wrapError("create acls", err)
+	}
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return newError("create acls", result.Error)
+		}
+	}
+
+	c.logger.Info("acls created", "count", len(acls))
+	return nil
+}
+
+// DeleteACLs deletes the ACLs matching filter and returns them.
+func (c *Client) DeleteACLs(ctx context.Context, filter model.ACLFilter) (_ []model.ACL, err error) {
+	defer c.observe("DeleteACLs", time.Now(), &err)
+
+	binding, err := filterBinding(filter)
+	if err != nil {
+		return nil, err
+	}
+	results, err := c.admin.DeleteACLs(ctx, kafka.ACLBindingFilters{binding})
+	if err != nil {
+		return nil, wrapError("delete acls", err)
+	}
+
+	deleted := make([]model.ACL, 0)
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return nil, newError("delete acls", result.Error)
+		}
+		deleted = append(deleted, fromBindings(result.ACLBindings)...)
+	}
+
+	c.logger.Info("acls deleted", "count", len(deleted))
+	return deleted, nil
+}
+
+// aclBinding converts acl for creation. Pattern type defaults to LITERAL,
+// host to * and permission to ALLOW.
+func aclBinding(acl model.ACL) (kafka.ACLBinding, error) {
+	if acl.PatternType == "" {
+		acl.PatternType = "LITERAL"
+	}
+	if acl.Host == "" {
+		acl.Host = "*"
+	}
+	if acl.Permission == "" {
+		acl.Permission = "ALLOW"
+	}
+	return toBinding(acl)
+}
+
+// filterBinding converts a filter, where empty fields match anything.
+func filterBinding(f model.ACLFilter) (kafka.ACLBindingFilter, error) {
+	acl := model.ACL{
+		ResourceType: orAny(f.ResourceType),
+		ResourceName: f.ResourceName,
+		PatternType:  orAny(f.PatternType),
+		Principal:    f.Principal,
+		Host:         f.Host,
+		Operation:    orAny(f.Operation),
+		Permission:   orAny(f.Permission),
+	}
+	return toBinding(acl)
+}
+
+func orAny(s string) string {
+	if s == "" {
+		return "ANY"
+	}
+	return s
+}
+
+func toBinding(acl model.ACL) (kafka.ACLBinding, error) {
+	resourceType, err := kafka.ResourceTypeFromString(acl.ResourceType)
+	if err != nil {
+		return kafka.ACLBinding{}, errorf(kafka.ErrInvalidArg, "unknown resource type %q", acl.ResourceType)
+	}
+	patternType, err := kafka.ResourcePatternTypeFromString(acl.PatternType)
+	if err != nil {
+		return kafka.ACLBinding{}, errorf(kafka.ErrInvalidArg, "unknown pattern type %q", acl.PatternType)
+	}
+	operation, err := kafka.ACLOperationFromString(acl.Operation)
+	if err != nil {
+		return kafka.ACLBinding{}, errorf(kafka.ErrInvalidArg, "unknown operation %q", acl.Operation)
+	}
+	permission, err := kafka.ACLPermissionTypeFromString(acl.Permission)
+	if err != nil {
+		return kafka.ACLBinding{}, errorf(kafka.ErrInvalidArg, "unknown permission %q", acl.Permission)
+	}
+	return kafka.ACLBinding{
+		Type:                resourceType,
+		Name:                acl.ResourceName,
+		ResourcePatternType: patternType,
+		Principal:           acl.Principal,
+		Host:                acl.Host,
+		Operation:           operation,
+		PermissionType:      permission,
+	}, nil
+}
+
+func fromBindings(bindings kafka.ACLBindings) []model.ACL {
+	acls := make([]model.ACL, 0, len(bindings))
+	for _, b := range bindings {
+		acls = append(acls, model.ACL{
+			ResourceType: b.Type.String(),
+			ResourceName: b.Name,
+			PatternType:  b.ResourcePatternType.String(),
+			Principal:    b.Principal,
+			Host:         b.Host,
+			Operation:    b.Operation.String(),
+			Permission:   b.PermissionType.String(),
+		})
+	}
+	return acls
+}
@@ -0,0 +1,57 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kafka-admin-api/internal/model"
+)
+
+func TestACLBindingDefaults(t *testing.T) {
+	binding, err := aclBinding(model.ACL{
+		ResourceType: "topic",
+		ResourceName: "orders",
+		Principal:    "User:alice",
+		Operation:    "write",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, kafka.ResourceTopic, binding.Type)
+	assert.Equal(t, kafka.ResourcePatternTypeLiteral, binding.ResourcePatternType)
+	assert.Equal(t, "*", binding.Host)
+	assert.Equal(t, kafka.ACLOperationWrite, binding.Operation)
+	assert.Equal(t, kafka.ACLPermissionTypeAllow, binding.PermissionType)
+
+	acls := fromBindings(kafka.ACLBindings{binding})
+	assert.Equal(t, []model.ACL{{
+		ResourceType: "TOPIC",
+		ResourceName: "orders",
+		PatternType:  "LITERAL",
+		Principal:    "User:alice",
+		Host:         "*",
+		Operation:    "WRITE",
+		Permission:   "ALLOW",
+	}}, acls)
+}
+
+func TestFilterBindingMatchesAnyByDefault(t *testing.T) {
+	binding, err := filterBinding(model.ACLFilter{Principal: "User:alice"})
+	require.NoError(t, err)
+	assert.Equal(t, kafka.ResourceAny, binding.Type)
+	assert.Equal(t, kafka.ResourcePatternTypeAny, binding.ResourcePatternType)
+	assert.Equal(t, kafka.ACLOperationAny, binding.Operation)
+	assert.Equal(t, kafka.ACLPermissionTypeAny, binding.PermissionType)
+	assert.Equal(t, "User:alice", binding.Principal)
+	assert.Empty(t, binding.Name)
+}
+
+func TestACLBindingRejectsUnknownValues(t *testing.T) {
+	_, err := aclBinding(model.ACL{ResourceType: "TOPIC", Operation: "PUBLISH"})
+	var kerr *Error
+	require.True(t, errors.As(err, &kerr))
+	assert.Equal(t, kafka.ErrInvalidArg, kerr.Code())
+	assert.Contains(t, err.Error(), `unknown operation "PUBLISH"`)
+}
@@ -0,0 +1,234 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"kafka-admin-api/internal/codec"
+	"kafka-admin-api/internal/model"
+)
+
+// browseGroupID only satisfies the consumer constructor. Browsing uses
+// manual assignment with commits disabled, so the group never joins the
+// coordinator and no offsets are stored for it.
+const browseGroupID = "kafka-admin-api-browser"
+
+// partitionRange is the half-open offset range [start, stop) to read from
+// one partition.
+type partitionRange struct {
+	start int64
+	stop  int64
+	high  int64
+}
+
+// BrowseMessages reads a page of messages from topic without joining a
+// consumer group. Each selected partition is read from its start position
+// for at most req.Limit messages (req.Last when browsing the tail), and the
+// page is returned ordered by partition and offset. Messages are converted
+// with renderer.
+func (c *Client) BrowseMessages(ctx context.Context, topic string, req model.BrowseRequest, renderer codec.Renderer) (_ *model.BrowsePage, err error) {
+	defer c.observe("BrowseMessages", time.Now(), &err)
+
+	config := &kafka.ConfigMap{
+		"bootstrap.servers":        c.config.BootstrapServers,
+		"group.id":                 browseGroupID,
+		"enable.auto.commit":       false,
+		"enable.auto.offset.store": false,
+		"enable.partition.eof":     true,
+	}
+	c.config.applySecurity(config)
+
+	consumer, err := kafka.NewConsumer(config)
+	if err != nil {
+		return nil, wrapError("create consumer", err)
+	}
+	defer consumer.Close()
+
+	partitions, err := c.browsePartitions(topic, req.Partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges, err := browseRanges(consumer, topic, partitions, req)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := make([]kafka.TopicPartition, 0, len(ranges))
+	remaining := make(map[int32]bool, len(ranges))
+	for p, r := range ranges {
+		if r.start >= r.stop {
+			continue
+		}
+		assignment = append(assignment, kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: p,
+			Offset:    kafka.Offset(r.start),
+		})
+		remaining[p] = true
+	}
+
+	next := make(map[int32]int64, len(ranges))
+	for p, r := range ranges {
+		next[p] = r.start
+	}
+
+	messages := make([]model.Message, 0)
+	if len(assignment) > 0 {
+		if err := consumer.Assign(assignment); err != nil {
+			return nil, wrapError("assign", err)
+		}
+
+	read:
+		for len(remaining) > 0 {
+			select {
+			case <-ctx.Done():
+				c.logger.Info("browse timed out", "topic", topic, "partitions_pending", len(remaining))
+				break read
+			default:
+			}
+
+			switch ev := consumer.Poll(100).(type) {
+			case *kafka.Message:
+				p := ev.TopicPartition.Partition
+				if !remaining[p] {
+					continue
+				}
+				offset := int64(ev.TopicPartition.Offset)
+				messages = append(messages, renderer.Message(ctx, ev))
+				next[p] = offset + 1
+				if next[p] >= ranges[p].stop {
+					delete(remaining, p)
+				}
+			case kafka.PartitionEOF:
+				delete(remaining, ev.Partition)
+			case kafka.Error:
+				if ev.IsFatal() {
+					return nil, wrapError(fmt.Sprintf("browse %s", topic), ev)
+				}
+				c.logger.Error("browse consumer error", "error", ev)
+			}
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if messages[i].Partition != messages[j].Partition {
+			return messages[i].Partition < messages[j].Partition
+		}
+		return messages[i].Offset < messages[j].Offset
+	})
+
+	hasMore := false
+	for p, r := range ranges {
+		if next[p] < r.high {
+			hasMore = true
+		}
+	}
+
+	return &model.BrowsePage{
+		Topic:       topic,
+		Count:       len(messages),
+		Messages:    messages,
+		NextOffsets: next,
+		HasMore:     hasMore,
+	}, nil
+}
+
+// browsePartitions validates the requested partitions against the topic
+// metadata, defaulting to every partition of the topic.
+func (c *Client) browsePartitions(topic string, requested []int32) ([]int32, error) {
+	metadata, err := c.admin.GetMetadata(&topic, false, 10000)
+	if err != nil {
+		return nil, wrapError("get metadata", err)
+	}
+	t, err := topicMetadata(metadata, topic)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Partitions) == 0 {
+		return nil, errorf(kafka.ErrUnknownTopicOrPart, "topic %s not found", topic)
+	}
+
+	existing := make(map[int32]bool, len(t.Partitions))
+	all := make([]int32, 0, len(t.Partitions))
+	for _, p := range t.Partitions {
+		existing[p.ID] = true
+		all = append(all, p.ID)
+	}
+	if len(requested) == 0 {
+		return all, nil
+	}
+
+	for _, p := range requested {
+		if !existing[p] {
+			return nil, errorf(kafka.ErrUnknownTopicOrPart, "partition %d does not exist in topic %s", p, topic)
+		}
+	}
+	return requested, nil
+}
+
+// browseRanges resolves where reading starts and stops in every partition.
+// The start is taken from the cursor, the tail (last N), a timestamp via
+// OffsetsForTimes or an explicit offset, in that order of precedence, and
+// defaults to the earliest offset.
+func browseRanges(consumer *kafka.Consumer, topic string, partitions []int32, req model.BrowseRequest) (map[int32]partitionRange, error) {
+	var byTime map[int32]int64
+	if req.Timestamp != nil && req.Cursor == nil && req.Last <= 0 {
+		times := make([]kafka.TopicPartition, 0, len(partitions))
+		for _, p := range partitions {
+			times = append(times, kafka.TopicPartition{Topic: &topic, Partition: p, Offset: kafka.Offset(*req.Timestamp)})
+		}
+		offsets, err := consumer.OffsetsForTimes(times, 10000)
+		if err != nil {
+			return nil, wrapError("offsets for times", err)
+		}
+		byTime = make(map[int32]int64, len(offsets))
+		for _, tp := range offsets {
+			byTime[tp.Partition] = int64(tp.Offset)
+		}
+	}
+
+	ranges := make(map[int32]partitionRange, len(partitions))
+	for _, p := range partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(topic, p, 10000)
+		if err != nil {
+			return nil, wrapError(fmt.Sprintf("query watermark offsets %s[%d]", topic, p), err)
+		}
+
+		limit := int64(req.Limit)
+		start := low
+		switch {
+		case req.Cursor != nil:
+			if offset, ok := req.Cursor[p]; ok {
+				start = offset
+			}
+		case req.Last > 0:
+			limit = int64(req.Last)
+			start = high - limit
+		case byTime != nil:
+			start = byTime[p]
+			if start < 0 {
+				start = high
+			}
+		case req.Offset != nil:
+			start = *req.Offset
+		}
+
+		if start < low {
+			start = low
+		}
+		if start > high {
+			start = high
+		}
+		stop := start + limit
+		if stop > high {
+			stop = high
+		}
+		ranges[p] = partitionRange{start: start, stop: stop, high: high}
+	}
+	return ranges, nil
+}
@@ -7,20 +7,35 @@ import (
 	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/twmb/franz-go/pkg/kadm"
 
+	"kafka-admin-api/internal/codec"
 	"kafka-admin-api/internal/model"
 )
 
 type Config struct {
-	BootstrapServers string
-	Username         string
-	Password         string
-	CALocation       string
+	BootstrapServers    string
+	Username            string
+	Password            string
+	CALocation          string
+	ProducerAcks        string
+	ProducerIdempotence bool
+	// Observer, when set, is told about the latency and outcome of every
+	// Client call.
+	Observer Observer
+}
+
+// Observer records Kafka client call metrics.
+type Observer interface {
+	ObserveKafkaCall(method string, duration time.Duration, err error)
 }
 
 type Client struct {
-	config Config
-	admin  *kafka.AdminClient
+	config   Config
+	admin    *kafka.AdminClient
+	producer *kafka.Producer
+	// franz serves the admin APIs the librdkafka client lacks.
+	franz  *kadm.Client
 	logger *slog.Logger
 }
 
@@ -29,6 +44,34 @@ func NewClient(cfg Config, logger *slog.Logger) (*Client, error) {
 		"bootstrap.servers": cfg.BootstrapServers,
 	}
 
+	cfg.applySecurity(config)
+
+	admin, err := kafka.NewAdminClient(config)
+	if err != nil {
+		return nil, wrapError("create admin client", err)
+	}
+
+	logger.Info("kafka admin client created", "bootstrap_servers", cfg.BootstrapServers)
+
+	producer, err := newProducer(cfg, logger)
+	if err != nil {
+		admin.Close()
+		return nil, err
+	}
+
+	franz, err := newFranzClient(cfg)
+	if err != nil {
+		producer.Close()
+		admin.Close()
+		return nil, err
+	}
+
+	return &Client{config: cfg, admin: admin, producer: producer, franz: franz, logger: logger}, nil
+}
+
+// applySecurity enables SASL_SSL with SCRAM-SHA-512 when credentials are
+// configured. It is shared by the admin client, producer and consumers.
+func (cfg Config) applySecurity(config *kafka.ConfigMap) {
 	if cfg.Username != "" && cfg.Password != "" {
 		_ = config.SetKey("security.protocol", "SASL_SSL")
 		_ = config.SetKey("sasl.mechanisms", "SCRAM-SHA-512")
@@ -38,25 +81,28 @@ func NewClient(cfg Config, logger *slog.Logger) (*Client, error) {
 			_ = config.SetKey("ssl.ca.location", cfg.CALocation)
 		}
 	}
+}
 
-	admin, err := kafka.NewAdminClient(config)
-	if err != nil {
-		return nil, fmt.Errorf("create admin client: %w", err)
+func (c *Client) observe(method string, start time.Time, err *error) {
+	if c.config.Observer != nil {
+		c.config.Observer.ObserveKafkaCall(method, time.Since(start), *err)
 	}
-
-	logger.Info("kafka admin client created", "bootstrap_servers", cfg.BootstrapServers)
-	return &Client{config: cfg, admin: admin, logger: logger}, nil
 }
 
 func (c *Client) Close() {
+	c.producer.Flush(5000)
+	c.producer.Close()
+	c.franz.Close()
 	c.admin.Close()
 	c.logger.Info("kafka admin client closed")
 }
 
-func (c *Client) ListBrokers(_ context.Context) ([]model.Broker, error) {
+func (c *Client) ListBrokers(_ context.Context) (_ []model.Broker, err error) {
+	defer c.observe("ListBrokers", time.Now(), &err)
+
 	metadata, err := c.admin.GetMetadata(nil, true, 10000)
 	if err != nil {
-		return nil, fmt.Errorf("get metadata: %w", err)
+		return nil, wrapError("get metadata", err)
 	}
 
 	brokers := make([]model.Broker, 0, len(metadata.Brokers))
@@ -70,10 +116,46 @@ func (c *Client) ListBrokers(_ context.Context) ([]model.Broker, error) {
 	return brokers, nil
 }
 
-func (c *Client) ListTopics(_ context.Context) ([]model.Topic, error) {
+// DescribeCluster returns the brokers and the active controller. The call is
+// bounded by ctx, which makes it suitable for readiness checks.
+func (c *Client) DescribeCluster(ctx context.Context) (_ *model.Cluster, err error) {
+	defer c.observe("DescribeCluster", time.Now(), &err)
+
+	var opts []kafka.DescribeClusterAdminOption
+	if deadline, ok := ctx.Deadline(); ok {
+		opts = append(opts, kafka.SetAdminRequestTimeout(time.Until(deadline)))
+	}
+	result, err := c.admin.DescribeCluster(ctx, opts...)
+	if err != nil {
+		return nil, wrapError("describe cluster", err)
+	}
+
+	cluster := &model.Cluster{
+		ControllerID: -1,
+		Brokers:      make([]model.Broker, 0, len(result.Nodes)),
+	}
+	if result.ClusterID != nil {
+		cluster.ClusterID = *result.ClusterID
+	}
+	if result.Controller != nil {
+		cluster.ControllerID = int32(result.Controller.ID)
+	}
+	for _, n := range result.Nodes {
+		cluster.Brokers = append(cluster.Brokers, model.Broker{
+			ID:   int32(n.ID),
+			Host: n.Host,
+			Port: int32(n.Port),
+		})
+	}
+	return cluster, nil
+}
+
+func (c *Client) ListTopics(_ context.Context) (_ []model.Topic, err error) {
+	defer c.observe("ListTopics", time.Now(), &err)
+
 	metadata, err := c.admin.GetMetadata(nil, true, 10000)
 	if err != nil {
-		return nil, fmt.Errorf("get metadata: %w", err)
+		return nil, wrapError("get metadata", err)
 	}
 
 	topics := make([]model.Topic, 0)
@@ -94,15 +176,17 @@ func (c *Client) ListTopics(_ context.Context) ([]model.Topic, error) {
 	return topics, nil
 }
 
-func (c *Client) GetTopic(ctx context.Context, name string) (*model.TopicDetail, error) {
+func (c *Client) GetTopic(ctx context.Context, name string) (_ *model.TopicDetail, err error) {
+	defer c.observe("GetTopic", time.Now(), &err)
+
 	metadata, err := c.admin.GetMetadata(&name, false, 10000)
 	if err != nil {
-		return nil, fmt.Errorf("get metadata: %w", err)
+		return nil, wrapError("get metadata", err)
 	}
 
-	t, exists := metadata.Topics[name]
-	if !exists {
-		return nil, fmt.Errorf("topic %s not found", name)
+	t, err := topicMetadata(metadata, name)
+	if err != nil {
+		return nil, err
 	}
 
 	partitions := make([]model.Partition, 0, len(t.Partitions))
@@ -121,11 +205,14 @@ func (c *Client) GetTopic(ctx context.Context, name string) (*model.TopicDetail,
 	}
 	results, err := c.admin.DescribeConfigs(ctx, []kafka.ConfigResource{configResource})
 	if err != nil {
-		return nil, fmt.Errorf("describe configs: %w", err)
+		return nil, wrapError("describe configs", err)
 	}
 
 	configs := make(map[string]string)
 	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return nil, newError("describe configs", result.Error)
+		}
 		for _, entry := range result.Config {
 			if !entry.IsDefault {
 				configs[entry.Name] = entry.Value
@@ -140,7 +227,23 @@ func (c *Client) GetTopic(ctx context.Context, name string) (*model.TopicDetail,
 	}, nil
 }
 
-func (c *Client) CreateTopic(ctx context.Context, req model.CreateTopicRequest) error {
+// topicMetadata returns the metadata of a topic requested by name.
+// librdkafka always includes a requested topic in the response and reports
+// a missing one through its Error instead.
+func topicMetadata(metadata *kafka.Metadata, name string) (kafka.TopicMetadata, error) {
+	t, exists := metadata.Topics[name]
+	if !exists {
+		return t, errorf(kafka.ErrUnknownTopicOrPart, "topic %s not found", name)
+	}
+	if t.Error.Code() != kafka.ErrNoError {
+		return t, newError("get metadata", t.Error)
+	}
+	return t, nil
+}
+
+func (c *Client) CreateTopic(ctx context.Context, req model.CreateTopicRequest) (err error) {
+	defer c.observe("CreateTopic", time.Now(), &err)
+
 	spec := kafka.TopicSpecification{
 		Topic:             req.Name,
 		NumPartitions:     int(req.Partitions),
@@ -151,12 +254,12 @@ func (c *Client) CreateTopic(ctx context.Context, req model.CreateTopicRequest)
 	results, err := c.admin.CreateTopics(ctx, []kafka.TopicSpecification{spec},
 		kafka.SetAdminOperationTimeout(30*time.Second))
 	if err != nil {
-		return fmt.Errorf("create topic: %w", err)
+		return wrapError("create topic", err)
 	}
 
 	for _, result := range results {
 		if result.Error.Code() != kafka.ErrNoError {
-			return fmt.Errorf("create topic %s: %s", result.Topic, result.Error.String())
+			return newError("create topic "+result.Topic, result.Error)
 		}
 	}
 
@@ -164,7 +267,9 @@ func (c *Client) CreateTopic(ctx context.Context, req model.CreateTopicRequest)
 	return nil
 }
 
-func (c *Client) UpdateTopicConfig(ctx context.Context, name string, configs map[string]string) error {
+func (c *Client) UpdateTopicConfig(ctx context.Context, name string, configs map[string]string) (err error) {
+	defer c.observe("UpdateTopicConfig", time.Now(), &err)
+
 	var configEntries []kafka.ConfigEntry
 	for k, v := range configs {
 		configEntries = append(configEntries, kafka.ConfigEntry{
@@ -181,12 +286,12 @@ func (c *Client) UpdateTopicConfig(ctx context.Context, name string, configs map
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("alter configs: %w", err)
+		return wrapError("alter configs", err)
 	}
 
 	for _, result := range results {
 		if result.Error.Code() != kafka.ErrNoError {
-			return fmt.Errorf("alter config: %s", result.Error.String())
+			return newError("alter config", result.Error)
 		}
 	}
 
@@ -194,10 +299,56 @@ func (c *Client) UpdateTopicConfig(ctx context.Context, name string, configs map
 	return nil
 }
 
-func (c *Client) ListConsumerGroups(ctx context.Context) ([]model.ConsumerGroup, error) {
+func (c *Client) CreatePartitions(ctx context.Context, name string, count int32, assignment [][]int32) (err error) {
+	defer c.observe("CreatePartitions", time.Now(), &err)
+
+	spec := kafka.PartitionsSpecification{
+		Topic:             name,
+		IncreaseTo:        int(count),
+		ReplicaAssignment: assignment,
+	}
+
+	results, err := c.admin.CreatePartitions(ctx, []kafka.PartitionsSpecification{spec},
+		kafka.SetAdminOperationTimeout(30*time.Second))
+	if err != nil {
+		return wrapError("create partitions", err)
+	}
+
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return newError("create partitions "+result.Topic, result.Error)
+		}
+	}
+
+	c.logger.Info("topic partitions increased", "name", name, "partitions", count)
+	return nil
+}
+
+func (c *Client) DeleteTopic(ctx context.Context, name string) (err error) {
+	defer c.observe("DeleteTopic", time.Now(), &err)
+
+	results, err := c.admin.DeleteTopics(ctx, []string{name},
+		kafka.SetAdminOperationTimeout(30*time.Second))
+	if err != nil {
+		return wrapError("delete topic", err)
+	}
+
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return newError("delete topic "+result.Topic, result.Error)
+		}
+	}
+
+	c.logger.Info("topic deleted", "name", name)
+	return nil
+}
+
+func (c *Client) ListConsumerGroups(ctx context.Context) (_ []model.ConsumerGroup, err error) {
+	defer c.observe("ListConsumerGroups", time.Now(), &err)
+
 	result, err := c.admin.ListConsumerGroups(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("list consumer groups: %w", err)
+		return nil, wrapError("list consumer groups", err)
 	}
 
 	groups := make([]model.ConsumerGroup, 0, len(result.Valid))
@@ -210,21 +361,79 @@ func (c *Client) ListConsumerGroups(ctx context.Context) ([]model.ConsumerGroup,
 	return groups, nil
 }
 
-func (c *Client) GetConsumerGroup(ctx context.Context, groupID string) (*model.ConsumerGroupDetail, error) {
+func (c *Client) GetConsumerGroup(ctx context.Context, groupID string) (_ *model.ConsumerGroupDetail, err error) {
+	defer c.observe("GetConsumerGroup", time.Now(), &err)
+
 	result, err := c.admin.DescribeConsumerGroups(ctx, []string{groupID})
 	if err != nil {
-		return nil, fmt.Errorf("describe consumer group: %w", err)
+		return nil, wrapError("describe consumer group", err)
 	}
 
 	if len(result.ConsumerGroupDescriptions) == 0 {
-		return nil, fmt.Errorf("consumer group %s not found", groupID)
+		return nil, errorf(kafka.ErrGroupIDNotFound, "consumer group %s not found", groupID)
 	}
 
 	g := result.ConsumerGroupDescriptions[0]
 	if g.Error.Code() != kafka.ErrNoError {
-		return nil, fmt.Errorf("describe group: %s", g.Error.String())
+		return nil, newError("describe group", g.Error)
+	}
+
+	group := groupDetail(g)
+	members := group.Members
+
+	committed, err := c.committedOffsets(ctx, groupID)
+	if err != nil {
+		return nil, err
 	}
 
+	partitions := make([]partitionKey, 0, len(committed))
+	seen := make(map[partitionKey]bool, len(committed))
+	for key := range committed {
+		seen[key] = true
+		partitions = append(partitions, key)
+	}
+	for _, m := range members {
+		for _, a := range m.Assignment {
+			key := partitionKey{topic: a.Topic, partition: a.Partition}
+			if !seen[key] {
+				seen[key] = true
+				partitions = append(partitions, key)
+			}
+		}
+	}
+
+	highWatermarks, err := c.listOffsets(ctx, partitions, kafka.LatestOffsetSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Topics, group.TotalLag = groupLag(members, committed, highWatermarks)
+	return &group, nil
+}
+
+// DescribeConsumerGroups returns the state, coordinator and members of
+// groups in one request. Unlike GetConsumerGroup it reads no offsets, so
+// Topics and TotalLag are left empty.
+func (c *Client) DescribeConsumerGroups(ctx context.Context, groupIDs []string) (_ []model.ConsumerGroupDetail, err error) {
+	defer c.observe("DescribeConsumerGroups", time.Now(), &err)
+
+	result, err := c.admin.DescribeConsumerGroups(ctx, groupIDs)
+	if err != nil {
+		return nil, wrapError("describe consumer groups", err)
+	}
+
+	groups := make([]model.ConsumerGroupDetail, 0, len(result.ConsumerGroupDescriptions))
+	for _, g := range result.ConsumerGroupDescriptions {
+		if g.Error.Code() != kafka.ErrNoError {
+			return nil, newError("describe group", g.Error)
+		}
+		groups = append(groups, groupDetail(g))
+	}
+	return groups, nil
+}
+
+// groupDetail converts a group description without offsets or lag.
+func groupDetail(g kafka.ConsumerGroupDescription) model.ConsumerGroupDetail {
 	members := make([]model.Member, 0, len(g.Members))
 	for _, m := range g.Members {
 		assignments := make([]model.TopicPartition, 0)
@@ -242,7 +451,7 @@ func (c *Client) GetConsumerGroup(ctx context.Context, groupID string) (*model.C
 		})
 	}
 
-	return &model.ConsumerGroupDetail{
+	return model.ConsumerGroupDetail{
 		GroupID: g.GroupID,
 		State:   g.State.String(),
 		Coordinator: model.Broker{
@@ -251,10 +460,72 @@ func (c *Client) GetConsumerGroup(ctx context.Context, groupID string) (*model.C
 			Port: int32(g.Coordinator.Port),
 		},
 		Members: members,
-	}, nil
+	}
 }
 
-func (c *Client) CreateConsumer(groupID, autoOffset string) (*kafka.Consumer, error) {
+func (c *Client) DeleteConsumerGroups(ctx context.Context, groupIDs []string) (_ []model.ConsumerGroupResult, err error) {
+	defer c.observe("DeleteConsumerGroups", time.Now(), &err)
+
+	result, err := c.admin.DeleteConsumerGroups(ctx, groupIDs,
+		kafka.SetAdminRequestTimeout(30*time.Second))
+	if err != nil {
+		return nil, wrapError("delete consumer groups", err)
+	}
+
+	results := make([]model.ConsumerGroupResult, 0, len(result.ConsumerGroupResults))
+	for _, r := range result.ConsumerGroupResults {
+		gr := model.ConsumerGroupResult{GroupID: r.Group, Deleted: true}
+		if r.Error.Code() != kafka.ErrNoError {
+			gr.Deleted = false
+			gr.Error = r.Error.String()
+		} else {
+			c.logger.Info("consumer group deleted", "group_id", r.Group)
+		}
+		results = append(results, gr)
+	}
+	return results, nil
+}
+
+// DeleteConsumerGroupOffsets removes the committed offsets of groupID for
+// the partitions of topic it has committed, with the OffsetDelete API that
+// confluent-kafka-go does not expose. Offsets of other topics are kept. The
+// broker refuses while a member is subscribed to topic.
+func (c *Client) DeleteConsumerGroupOffsets(ctx context.Context, groupID, topic string) (_ *model.ConsumerGroupResult, err error) {
+	defer c.observe("DeleteConsumerGroupOffsets", time.Now(), &err)
+
+	committed, err := c.committedOffsets(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions kadm.TopicsSet
+	for key := range committed {
+		if key.topic == topic {
+			partitions.Add(topic, key.partition)
+		}
+	}
+	if len(partitions) == 0 {
+		return &model.ConsumerGroupResult{
+			GroupID: groupID,
+			Topic:   topic,
+			Error:   fmt.Sprintf("group has no committed offsets for topic %s", topic),
+		}, nil
+	}
+
+	responses, err := c.franz.DeleteOffsets(ctx, groupID, partitions)
+	if err == nil {
+		err = responses.Error()
+	}
+	if err != nil {
+		return nil, franzError("delete consumer group offsets", err)
+	}
+	c.logger.Info("consumer group offsets deleted", "group_id", groupID, "topic", topic)
+	return &model.ConsumerGroupResult{GroupID: groupID, Topic: topic, Deleted: true}, nil
+}
+
+func (c *Client) CreateConsumer(groupID, autoOffset string) (_ *kafka.Consumer, err error) {
+	defer c.observe("CreateConsumer", time.Now(), &err)
+
 	config := &kafka.ConfigMap{
 		"bootstrap.servers":  c.config.BootstrapServers,
 		"group.id":           groupID,
@@ -262,19 +533,11 @@ func (c *Client) CreateConsumer(groupID, autoOffset string) (*kafka.Consumer, er
 		"enable.auto.commit": true,
 	}
 
-	if c.config.Username != "" && c.config.Password != "" {
-		_ = config.SetKey("security.protocol", "SASL_SSL")
-		_ = config.SetKey("sasl.mechanisms", "SCRAM-SHA-512")
-		_ = config.SetKey("sasl.username", c.config.Username)
-		_ = config.SetKey("sasl.password", c.config.Password)
-		if c.config.CALocation != "" {
-			_ = config.SetKey("ssl.ca.location", c.config.CALocation)
-		}
-	}
+	c.config.applySecurity(config)
 
 	consumer, err := kafka.NewConsumer(config)
 	if err != nil {
-		return nil, fmt.Errorf("create consumer: %w", err)
+		return nil, wrapError("create consumer", err)
 	}
 
 	c.logger.Info("consumer created", "group_id", groupID)
@@ -289,7 +552,7 @@ func (c *Client) ConsumeMessages(ctx context.Context, topic, groupID, autoOffset
 	defer consumer.Close()
 
 	if err := consumer.Subscribe(topic, nil); err != nil {
-		return fmt.Errorf("subscribe: %w", err)
+		return wrapError("subscribe", err)
 	}
 
 	c.logger.Info("consuming messages", "topic", topic, "group_id", groupID)
@@ -310,20 +573,7 @@ func (c *Client) ConsumeMessages(ctx context.Context, topic, groupID, autoOffset
 				continue
 			}
 
-			headers := make(map[string]string)
-			for _, h := range msg.Headers {
-				headers[h.Key] = string(h.Value)
-			}
-
-			m := model.Message{
-				Topic:     *msg.TopicPartition.Topic,
-				Partition: msg.TopicPartition.Partition,
-				Offset:    int64(msg.TopicPartition.Offset),
-				Key:       string(msg.Key),
-				Value:     string(msg.Value),
-				Timestamp: msg.Timestamp.UnixMilli(),
-				Headers:   headers,
-			}
+			m := codec.Message(msg, codec.UTF8)
 
 			select {
 			case msgChan <- m:
@@ -0,0 +1,140 @@
+package kafka
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"kafka-admin-api/internal/model"
+)
+
+var configSources = map[kafka.ConfigSource]string{
+	kafka.ConfigSourceDynamicTopic:         model.ConfigSourceDynamicTopic,
+	kafka.ConfigSourceDynamicBroker:        model.ConfigSourceDynamicBroker,
+	kafka.ConfigSourceDynamicDefaultBroker: model.ConfigSourceDynamicDefault,
+	kafka.ConfigSourceStaticBroker:         model.ConfigSourceStatic,
+	kafka.ConfigSourceDefault:              model.ConfigSourceDefault,
+}
+
+// sourcePrecedence orders config sources from the one that wins to the one
+// that is overridden by all others.
+var sourcePrecedence = map[string]int{
+	model.ConfigSourceDynamicTopic:   0,
+	model.ConfigSourceDynamicBroker:  1,
+	model.ConfigSourceDynamicDefault: 2,
+	model.ConfigSourceStatic:         3,
+	model.ConfigSourceDefault:        4,
+	model.ConfigSourceUnknown:        5,
+}
+
+// DescribeTopicConfigs describes every config of topic, including defaults,
+// with the synonyms the broker reports.
+func (c *Client) DescribeTopicConfigs(ctx context.Context, topic string) (_ []model.ConfigEntry, err error) {
+	defer c.observe("DescribeTopicConfigs", time.Now(), &err)
+
+	return c.describeConfigs(ctx, "describe topic configs", kafka.ConfigResource{Type: kafka.ResourceTopic, Name: topic})
+}
+
+// DescribeBrokerConfigs describes the configs of broker. An empty broker
+// describes the cluster-wide dynamic defaults, which only lists the keys
+// set at that level.
+func (c *Client) DescribeBrokerConfigs(ctx context.Context, broker string) (_ []model.ConfigEntry, err error) {
+	defer c.observe("DescribeBrokerConfigs", time.Now(), &err)
+
+	return c.describeConfigs(ctx, "describe broker configs", kafka.ConfigResource{Type: kafka.ResourceBroker, Name: broker})
+}
+
+func (c *Client) describeConfigs(ctx context.Context, op string, resource kafka.ConfigResource) ([]model.ConfigEntry, error) {
+	results, err := c.admin.DescribeConfigs(ctx, []kafka.ConfigResource{resource})
+	if err != nil {
+		return nil, wrapError(op, err)
+	}
+
+	entries := make([]model.ConfigEntry, 0)
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return nil, newError(op, result.Error)
+		}
+		for _, entry := range result.Config {
+			entries = append(entries, configEntry(entry))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// AlterBrokerConfigs sets configs on broker, or cluster-wide when broker is
+// empty. Keys not listed keep their values.
+func (c *Client) AlterBrokerConfigs(ctx context.Context, broker string, configs map[string]string) (err error) {
+	defer c.observe("AlterBrokerConfigs", time.Now(), &err)
+
+	entries := make([]kafka.ConfigEntry, 0, len(configs))
+	for k, v := range configs {
+		entries = append(entries, kafka.ConfigEntry{Name: k, Value: v, IncrementalOperation: kafka.AlterConfigOpTypeSet})
+	}
+
+	results, err := c.admin.IncrementalAlterConfigs(ctx, []kafka.ConfigResource{
+		{Type: kafka.ResourceBroker, Name: broker, Config: entries},
+	})
+	if err != nil {
+		return wrapError("alter broker configs", err)
+	}
+	for _, result := range results {
+		if result.Error.Code() != kafka.ErrNoError {
+			return newError("alter broker configs", result.Error)
+		}
+	}
+
+	c.logger.Info("broker configs updated", "broker", broker, "keys", len(configs))
+	return nil
+}
+
+func configEntry(entry kafka.ConfigEntryResult) model.ConfigEntry {
+	e := model.ConfigEntry{
+		Name:      entry.Name,
+		Source:    configSource(entry.Source),
+		ReadOnly:  entry.IsReadOnly,
+		Sensitive: entry.IsSensitive,
+	}
+	if !entry.IsSensitive {
+		value := entry.Value
+		e.Value = &value
+	}
+	e.Synonyms = configSynonyms(entry)
+	return e
+}
+
+// configSynonyms orders the synonyms of entry by precedence. The client
+// returns them keyed by name, so a broker config set both dynamically and
+// statically under the same name only shows up once.
+func configSynonyms(entry kafka.ConfigEntryResult) []model.ConfigSynonym {
+	if len(entry.Synonyms) == 0 {
+		return nil
+	}
+	synonyms := make([]model.ConfigSynonym, 0, len(entry.Synonyms))
+	for _, syn := range entry.Synonyms {
+		s := model.ConfigSynonym{Name: syn.Name, Source: configSource(syn.Source)}
+		if !entry.IsSensitive {
+			value := syn.Value
+			s.Value = &value
+		}
+		synonyms = append(synonyms, s)
+	}
+	sort.Slice(synonyms, func(i, j int) bool {
+		pi, pj := sourcePrecedence[synonyms[i].Source], sourcePrecedence[synonyms[j].Source]
+		if pi != pj {
+			return pi < pj
+		}
+		return synonyms[i].Name < synonyms[j].Name
+	})
+	return synonyms
+}
+
+func configSource(source kafka.ConfigSource) string {
+	if s, ok := configSources[source]; ok {
+		return s
+	}
+	return model.ConfigSourceUnknown
+}
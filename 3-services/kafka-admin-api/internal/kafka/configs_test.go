@@ -0,0 +1,47 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kafka-admin-api/internal/model"
+)
+
+func TestConfigEntrySynonymsByPrecedence(t *testing.T) {
+	entry := configEntry(kafka.ConfigEntryResult{
+		Name:   "retention.ms",
+		Value:  "86400000",
+		Source: kafka.ConfigSourceDynamicBroker,
+		Synonyms: map[string]kafka.ConfigEntryResult{
+			"log.retention.hours": {Name: "log.retention.hours", Value: "168", Source: kafka.ConfigSourceDefault},
+			"log.retention.ms":    {Name: "log.retention.ms", Value: "86400000", Source: kafka.ConfigSourceDynamicBroker},
+		},
+	})
+
+	assert.Equal(t, model.ConfigSourceDynamicBroker, entry.Source)
+	require.Len(t, entry.Synonyms, 2)
+	assert.Equal(t, "log.retention.ms", entry.Synonyms[0].Name)
+	assert.Equal(t, model.ConfigSourceDynamicBroker, entry.Synonyms[0].Source)
+	assert.Equal(t, "log.retention.hours", entry.Synonyms[1].Name)
+	assert.Equal(t, "168", *entry.Synonyms[1].Value)
+}
+
+func TestConfigEntryHidesSensitiveValues(t *testing.T) {
+	entry := configEntry(kafka.ConfigEntryResult{
+		Name:        "ssl.keystore.password",
+		Value:       "",
+		Source:      kafka.ConfigSourceStaticBroker,
+		IsSensitive: true,
+		Synonyms: map[string]kafka.ConfigEntryResult{
+			"ssl.keystore.password": {Name: "ssl.keystore.password", Source: kafka.ConfigSourceStaticBroker},
+		},
+	})
+
+	assert.Nil(t, entry.Value)
+	assert.Equal(t, model.ConfigSourceStatic, entry.Source)
+	require.Len(t, entry.Synonyms, 1)
+	assert.Nil(t, entry.Synonyms[0].Value)
+}
@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// Error is a failed Kafka operation that keeps the broker or client error
+// code, so callers can tell e.g. a missing topic from a timeout.
+type Error struct {
+	Op  string
+	Err kafka.Error
+}
+
+func (e *Error) Error() string {
+	if e.Op == "" {
+		return e.Err.Error()
+	}
+	return e.Op + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Code() kafka.ErrorCode {
+	return e.Err.Code()
+}
+
+// Name returns the protocol name of the error code, e.g. TOPIC_ALREADY_EXISTS.
+func (e *Error) Name() string {
+	if name, ok := codeNames[e.Err.Code()]; ok {
+		return name
+	}
+	return fmt.Sprintf("KAFKA_ERROR_%d", int(e.Err.Code()))
+}
+
+// Timeout reports whether the operation timed out on the client or broker.
+func (e *Error) Timeout() bool {
+	switch e.Err.Code() {
+	case kafka.ErrTimedOut, kafka.ErrTimedOutQueue, kafka.ErrRequestTimedOut:
+		return true
+	}
+	return e.Err.IsTimeout()
+}
+
+// codeNames covers the codes the API is expected to surface. librdkafka's
+// rd_kafka_err2name is not exposed by confluent-kafka-go.
+var codeNames = map[kafka.ErrorCode]string{
+	kafka.ErrTransport:                  "TRANSPORT",
+	kafka.ErrAllBrokersDown:             "ALL_BROKERS_DOWN",
+	kafka.ErrInvalidArg:                 "INVALID_ARG",
+	kafka.ErrTimedOut:                   "TIMED_OUT",
+	kafka.ErrTimedOutQueue:              "TIMED_OUT_QUEUE",
+	kafka.ErrUnknownTopic:               "UNKNOWN_TOPIC",
+	kafka.ErrUnknownTopicOrPart:         "UNKNOWN_TOPIC_OR_PART",
+	kafka.ErrRequestTimedOut:            "REQUEST_TIMED_OUT",
+	kafka.ErrTopicAuthorizationFailed:   "TOPIC_AUTHORIZATION_FAILED",
+	kafka.ErrGroupAuthorizationFailed:   "GROUP_AUTHORIZATION_FAILED",
+	kafka.ErrClusterAuthorizationFailed: "CLUSTER_AUTHORIZATION_FAILED",
+	kafka.ErrUnsupportedVersion:         "UNSUPPORTED_VERSION",
+	kafka.ErrTopicAlreadyExists:         "TOPIC_ALREADY_EXISTS",
+	kafka.ErrInvalidPartitions:          "INVALID_PARTITIONS",
+	kafka.ErrInvalidReplicationFactor:   "INVALID_REPLICATION_FACTOR",
+	kafka.ErrInvalidReplicaAssignment:   "INVALID_REPLICA_ASSIGNMENT",
+	kafka.ErrInvalidConfig:              "INVALID_CONFIG",
+	kafka.ErrNotController:              "NOT_CONTROLLER",
+	kafka.ErrInvalidRequest:             "INVALID_REQUEST",
+	kafka.ErrPolicyViolation:            "POLICY_VIOLATION",
+	kafka.ErrSecurityDisabled:           "SECURITY_DISABLED",
+	kafka.ErrSaslAuthenticationFailed:   "SASL_AUTHENTICATION_FAILED",
+	kafka.ErrNonEmptyGroup:              "NON_EMPTY_GROUP",
+	kafka.ErrGroupSubscribedToTopic:     "GROUP_SUBSCRIBED_TO_TOPIC",
+	kafka.ErrGroupIDNotFound:            "GROUP_ID_NOT_FOUND",
+	kafka.ErrTopicDeletionDisabled:      "TOPIC_DELETION_DISABLED",
+	kafka.ErrResourceNotFound:           "RESOURCE_NOT_FOUND",
+	kafka.ErrUnsupportedSaslMechanism:   "UNSUPPORTED_SASL_MECHANISM",
+	kafka.ErrUnacceptableCredential:     "UNACCEPTABLE_CREDENTIAL",
+}
+
+// wrapError attaches op to err, as *Error when err carries a Kafka error code.
+func wrapError(op string, err error) error {
+	var kerr kafka.Error
+	if errors.As(err, &kerr) {
+		return &Error{Op: op, Err: kerr}
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// newError reports a per-resource error from an admin result.
+func newError(op string, kerr kafka.Error) error {
+	return &Error{Op: op, Err: kerr}
+}
+
+// errorf builds an *Error for failures detected on the client side, such as
+// a topic missing from metadata.
+func errorf(code kafka.ErrorCode, format string, args ...any) error {
+	return &Error{Err: kafka.NewError(code, fmt.Sprintf(format, args...), false)}
+}
@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapError(t *testing.T) {
+	cause := fmt.Errorf("request: %w", kafka.NewError(kafka.ErrTopicAlreadyExists, "Topic 'orders' already exists.", false))
+
+	err := wrapError("create topic", cause)
+
+	var kerr *Error
+	assert.True(t, errors.As(err, &kerr))
+	assert.Equal(t, kafka.ErrTopicAlreadyExists, kerr.Code())
+	assert.Equal(t, "TOPIC_ALREADY_EXISTS", kerr.Name())
+	assert.Equal(t, "create topic: Topic 'orders' already exists.", err.Error())
+
+	plain := wrapError("create topic", errors.New("boom"))
+	assert.False(t, errors.As(plain, &kerr))
+	assert.Equal(t, "create topic: boom", plain.Error())
+}
+
+func TestErrorTimeout(t *testing.T) {
+	assert.True(t, errorf(kafka.ErrTimedOut, "timed out").(*Error).Timeout())
+	assert.True(t, newError("list offsets", kafka.NewError(kafka.ErrRequestTimedOut, "", false)).(*Error).Timeout())
+	assert.False(t, errorf(kafka.ErrUnknownTopicOrPart, "topic x not found").(*Error).Timeout())
+}
+
+func TestTopicMetadataErrors(t *testing.T) {
+	metadata := &kafka.Metadata{Topics: map[string]kafka.TopicMetadata{
+		"orders": {Topic: "orders", Partitions: []kafka.PartitionMetadata{{ID: 0}}},
+		"missing": {
+			Topic: "missing",
+			Error: kafka.NewError(kafka.ErrUnknownTopicOrPart, "Broker: Unknown topic or partition", false),
+		},
+	}}
+
+	topic, err := topicMetadata(metadata, "orders")
+	assert.NoError(t, err)
+	assert.Len(t, topic.Partitions, 1)
+
+	// librdkafka lists a requested topic even when it does not exist.
+	for _, name := range []string{"missing", "absent"} {
+		_, err = topicMetadata(metadata, name)
+		var kerr *Error
+		assert.True(t, errors.As(err, &kerr), name)
+		assert.Equal(t, kafka.ErrUnknownTopicOrPart, kerr.Code(), name)
+	}
+}
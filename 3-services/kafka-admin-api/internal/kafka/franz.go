@@ -0,0 +1,55 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// newFranzClient returns a franz-go admin client for the admin APIs
+// confluent-kafka-go v2.12 does not expose: client quotas and OffsetDelete.
+// It connects the same way as the librdkafka clients; see applySecurity.
+func newFranzClient(cfg Config) (*kadm.Client, error) {
+	opts := []kgo.Opt{kgo.SeedBrokers(strings.Split(cfg.BootstrapServers, ",")...)}
+	if cfg.Username != "" && cfg.Password != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cfg.CALocation != "" {
+			pem, err := os.ReadFile(cfg.CALocation)
+			if err != nil {
+				return nil, fmt.Errorf("read CA file: %w", err)
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("CA file %s contains no certificates", cfg.CALocation)
+			}
+		}
+		opts = append(opts,
+			kgo.DialTLSConfig(tlsConfig),
+			kgo.SASL(scram.Auth{User: cfg.Username, Pass: cfg.Password}.AsSha512Mechanism()))
+	}
+
+	client, err := kadm.NewOptClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create franz-go admin client: %w", err)
+	}
+	return client, nil
+}
+
+// franzError keeps the Kafka error code of a franz-go error, so its
+// failures map to the same statuses as the other admin calls.
+func franzError(op string, err error) error {
+	var kerror *kerr.Error
+	if errors.As(err, &kerror) {
+		return &Error{Op: op, Err: kafka.NewError(kafka.ErrorCode(kerror.Code), err.Error(), false)}
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,333 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"kafka-admin-api/internal/model"
+)
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+// committedOffsets returns the committed offset of every partition the group
+// has committed for, whether or not a member is currently assigned to it.
+func (c *Client) committedOffsets(ctx context.Context, groupID string) (map[partitionKey]int64, error) {
+	result, err := c.admin.ListConsumerGroupOffsets(ctx,
+		[]kafka.ConsumerGroupTopicPartitions{{Group: groupID}},
+		kafka.SetAdminRequireStableOffsets(true))
+	if err != nil {
+		return nil, wrapError("list consumer group offsets", err)
+	}
+
+	offsets := make(map[partitionKey]int64)
+	for _, g := range result.ConsumerGroupsTopicPartitions {
+		for _, tp := range g.Partitions {
+			if tp.Error != nil {
+				return nil, wrapError("list consumer group offsets "+groupID, tp.Error)
+			}
+			if tp.Offset < 0 {
+				continue
+			}
+			offsets[partitionKey{topic: *tp.Topic, partition: tp.Partition}] = int64(tp.Offset)
+		}
+	}
+	return offsets, nil
+}
+
+// listOffsets resolves spec (earliest, latest or a timestamp) for each
+// partition in a single ListOffsets request.
+func (c *Client) listOffsets(ctx context.Context, partitions []partitionKey, spec kafka.OffsetSpec) (map[partitionKey]int64, error) {
+	offsets := make(map[partitionKey]int64, len(partitions))
+	if len(partitions) == 0 {
+		return offsets, nil
+	}
+
+	req := make(map[kafka.TopicPartition]kafka.OffsetSpec, len(partitions))
+	for _, p := range partitions {
+		topic := p.topic
+		req[kafka.TopicPartition{Topic: &topic, Partition: p.partition}] = spec
+	}
+
+	result, err := c.admin.ListOffsets(ctx, req)
+	if err != nil {
+		return nil, wrapError("list offsets", err)
+	}
+
+	for tp, info := range result.ResultInfos {
+		if info.Error.Code() != kafka.ErrNoError {
+			return nil, newError(fmt.Sprintf("list offsets %s[%d]", *tp.Topic, tp.Partition), info.Error)
+		}
+		offsets[partitionKey{topic: *tp.Topic, partition: tp.Partition}] = int64(info.Offset)
+	}
+	return offsets, nil
+}
+
+// ConsumerGroupLag returns the committed offset, high watermark and lag of
+// every partition groupID has committed for. Unlike GetConsumerGroup it does
+// not describe the group, which keeps periodic collection cheap.
+func (c *Client) ConsumerGroupLag(ctx context.Context, groupID string) (_ []model.TopicPartition, err error) {
+	defer c.observe("ConsumerGroupLag", time.Now(), &err)
+
+	committed, err := c.committedOffsets(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions := make([]partitionKey, 0, len(committed))
+	for key := range committed {
+		partitions = append(partitions, key)
+	}
+	highWatermarks, err := c.listOffsets(ctx, partitions, kafka.LatestOffsetSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	topics, _ := groupLag(nil, committed, highWatermarks)
+	var lags []model.TopicPartition
+	for _, t := range topics {
+		lags = append(lags, t.Partitions...)
+	}
+	return lags, nil
+}
+
+// ResetConsumerGroupOffsets computes new committed offsets for groupID
+// according to req and, unless req.DryRun is set, commits them. Targets are
+// clamped to each partition's earliest/latest offsets like the kafka CLI.
+func (c *Client) ResetConsumerGroupOffsets(ctx context.Context, groupID string, req model.ResetOffsetsRequest) (_ []model.OffsetReset, err error) {
+	defer c.observe("ResetConsumerGroupOffsets", time.Now(), &err)
+
+	committed, err := c.committedOffsets(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, err := c.resetScope(committed, req)
+	if err != nil {
+		return nil, err
+	}
+
+	earliest, err := c.listOffsets(ctx, partitions, kafka.EarliestOffsetSpec)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := c.listOffsets(ctx, partitions, kafka.LatestOffsetSpec)
+	if err != nil {
+		return nil, err
+	}
+	var atTimestamp map[partitionKey]int64
+	if req.Strategy == model.ResetToTimestamp || req.Strategy == model.ResetToDatetime {
+		atTimestamp, err = c.listOffsets(ctx, partitions, kafka.NewOffsetSpecForTimestamp(*req.Timestamp))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resets := make([]model.OffsetReset, 0, len(partitions))
+	for _, p := range partitions {
+		current, ok := committed[p]
+		if !ok {
+			current = -1
+		}
+		timestampOffset := int64(-1)
+		if off, ok := atTimestamp[p]; ok {
+			timestampOffset = off
+		}
+		resets = append(resets, model.OffsetReset{
+			Topic:          p.topic,
+			Partition:      p.partition,
+			PreviousOffset: current,
+			NewOffset:      resetTarget(req, current, earliest[p], latest[p], timestampOffset),
+		})
+	}
+	sort.Slice(resets, func(i, j int) bool {
+		if resets[i].Topic != resets[j].Topic {
+			return resets[i].Topic < resets[j].Topic
+		}
+		return resets[i].Partition < resets[j].Partition
+	})
+
+	if req.DryRun {
+		return resets, nil
+	}
+
+	tps := make([]kafka.TopicPartition, 0, len(resets))
+	for _, r := range resets {
+		topic := r.Topic
+		tps = append(tps, kafka.TopicPartition{
+			Topic:     &topic,
+			Partition: r.Partition,
+			Offset:    kafka.Offset(r.NewOffset),
+		})
+	}
+
+	result, err := c.admin.AlterConsumerGroupOffsets(ctx,
+		[]kafka.ConsumerGroupTopicPartitions{{Group: groupID, Partitions: tps}})
+	if err != nil {
+		return nil, wrapError("alter consumer group offsets", err)
+	}
+	for _, g := range result.ConsumerGroupsTopicPartitions {
+		for _, tp := range g.Partitions {
+			if tp.Error != nil {
+				return nil, wrapError(fmt.Sprintf("alter consumer group offsets %s[%d]", *tp.Topic, tp.Partition), tp.Error)
+			}
+		}
+	}
+
+	c.logger.Info("consumer group offsets reset",
+		"group_id", groupID, "strategy", req.Strategy, "partitions", len(resets))
+	return resets, nil
+}
+
+// resetScope returns the partitions an offset reset applies to: the given
+// partitions of req.Topic, every partition of req.Topic, or every partition
+// the group has committed offsets for.
+func (c *Client) resetScope(committed map[partitionKey]int64, req model.ResetOffsetsRequest) ([]partitionKey, error) {
+	if req.Topic == "" {
+		if len(committed) == 0 {
+			return nil, errorf(kafka.ErrInvalidArg, "group has no committed offsets, a topic is required")
+		}
+		partitions := make([]partitionKey, 0, len(committed))
+		for key := range committed {
+			partitions = append(partitions, key)
+		}
+		return partitions, nil
+	}
+
+	topic := req.Topic
+	metadata, err := c.admin.GetMetadata(&topic, false, 10000)
+	if err != nil {
+		return nil, wrapError("get metadata", err)
+	}
+	t, err := topicMetadata(metadata, topic)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Partitions) == 0 {
+		return nil, errorf(kafka.ErrUnknownTopicOrPart, "topic %s not found", topic)
+	}
+
+	existing := make(map[int32]bool, len(t.Partitions))
+	for _, p := range t.Partitions {
+		existing[p.ID] = true
+	}
+
+	if len(req.Partitions) == 0 {
+		partitions := make([]partitionKey, 0, len(t.Partitions))
+		for _, p := range t.Partitions {
+			partitions = append(partitions, partitionKey{topic: topic, partition: p.ID})
+		}
+		return partitions, nil
+	}
+
+	partitions := make([]partitionKey, 0, len(req.Partitions))
+	for _, p := range req.Partitions {
+		if !existing[p] {
+			return nil, errorf(kafka.ErrUnknownTopicOrPart, "partition %d does not exist in topic %s", p, topic)
+		}
+		partitions = append(partitions, partitionKey{topic: topic, partition: p})
+	}
+	return partitions, nil
+}
+
+// resetTarget computes the new offset for one partition. current is -1 when
+// the group has not committed for the partition, and timestampOffset is -1
+// when no record exists at or after the requested timestamp.
+func resetTarget(req model.ResetOffsetsRequest, current, earliest, latest, timestampOffset int64) int64 {
+	var target int64
+	switch req.Strategy {
+	case model.ResetToEarliest:
+		return earliest
+	case model.ResetToLatest:
+		return latest
+	case model.ResetToTimestamp, model.ResetToDatetime:
+		if timestampOffset < 0 {
+			return latest
+		}
+		return timestampOffset
+	case model.ResetToOffset:
+		target = *req.Offset
+	case model.ResetShiftBy:
+		base := current
+		if base < 0 {
+			base = earliest
+		}
+		target = base + *req.Shift
+	}
+
+	if target < earliest {
+		return earliest
+	}
+	if target > latest {
+		return latest
+	}
+	return target
+}
+
+// groupLag fills Offset and Lag on every member assignment and rolls the
+// result up per topic. Partitions that only have a committed offset (no
+// active member, e.g. Empty groups) are included in the rollup as well.
+// Partitions without a committed offset report Offset -1 and no lag.
+func groupLag(members []model.Member, committed, highWatermarks map[partitionKey]int64) ([]model.TopicLag, int64) {
+	partitions := make(map[partitionKey]model.TopicPartition)
+
+	lagOf := func(key partitionKey) model.TopicPartition {
+		tp := model.TopicPartition{
+			Topic:        key.topic,
+			Partition:    key.partition,
+			Offset:       -1,
+			LogEndOffset: highWatermarks[key],
+		}
+		if offset, ok := committed[key]; ok {
+			tp.Offset = offset
+			if lag := tp.LogEndOffset - offset; lag > 0 {
+				tp.Lag = lag
+			}
+		}
+		return tp
+	}
+
+	for i := range members {
+		for j, a := range members[i].Assignment {
+			key := partitionKey{topic: a.Topic, partition: a.Partition}
+			tp := lagOf(key)
+			members[i].Assignment[j] = tp
+			partitions[key] = tp
+		}
+	}
+	for key := range committed {
+		if _, ok := partitions[key]; !ok {
+			partitions[key] = lagOf(key)
+		}
+	}
+
+	byTopic := make(map[string]*model.TopicLag)
+	var total int64
+	for _, tp := range partitions {
+		t, ok := byTopic[tp.Topic]
+		if !ok {
+			t = &model.TopicLag{Topic: tp.Topic}
+			byTopic[tp.Topic] = t
+		}
+		t.Lag += tp.Lag
+		t.Partitions = append(t.Partitions, tp)
+		total += tp.Lag
+	}
+
+	topics := make([]model.TopicLag, 0, len(byTopic))
+	for _, t := range byTopic {
+		sort.Slice(t.Partitions, func(i, j int) bool {
+			return t.Partitions[i].Partition < t.Partitions[j].Partition
+		})
+		topics = append(topics, *t)
+	}
+	sort.Slice(topics, func(i, j int) bool { return topics[i].Topic < topics[j].Topic })
+
+	return topics, total
+}
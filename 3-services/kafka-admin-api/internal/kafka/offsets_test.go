@@ -0,0 +1,76 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"kafka-admin-api/internal/model"
+)
+
+func TestGroupLag(t *testing.T) {
+	members := []model.Member{
+		{
+			MemberID: "member-1",
+			Assignment: []model.TopicPartition{
+				{Topic: "orders", Partition: 0},
+				{Topic: "orders", Partition: 1},
+			},
+		},
+	}
+	committed := map[partitionKey]int64{
+		{topic: "orders", partition: 0}:   90,
+		{topic: "payments", partition: 0}: 10,
+	}
+	highWatermarks := map[partitionKey]int64{
+		{topic: "orders", partition: 0}:   100,
+		{topic: "orders", partition: 1}:   50,
+		{topic: "payments", partition: 0}: 15,
+	}
+
+	topics, total := groupLag(members, committed, highWatermarks)
+
+	assert.Equal(t, int64(15), total)
+	assert.Len(t, topics, 2)
+
+	assert.Equal(t, "orders", topics[0].Topic)
+	assert.Equal(t, int64(10), topics[0].Lag)
+	assert.Len(t, topics[0].Partitions, 2)
+	assert.Equal(t, int64(-1), topics[0].Partitions[1].Offset)
+	assert.Equal(t, int64(0), topics[0].Partitions[1].Lag)
+
+	// Committed but unassigned partitions still count towards the rollup.
+	assert.Equal(t, "payments", topics[1].Topic)
+	assert.Equal(t, int64(5), topics[1].Lag)
+
+	assert.Equal(t, int64(90), members[0].Assignment[0].Offset)
+	assert.Equal(t, int64(10), members[0].Assignment[0].Lag)
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestResetTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      model.ResetOffsetsRequest
+		current  int64
+		atTime   int64
+		expected int64
+	}{
+		{"earliest", model.ResetOffsetsRequest{Strategy: model.ResetToEarliest}, 50, -1, 10},
+		{"latest", model.ResetOffsetsRequest{Strategy: model.ResetToLatest}, 50, -1, 100},
+		{"to offset", model.ResetOffsetsRequest{Strategy: model.ResetToOffset, Offset: int64Ptr(42)}, 50, -1, 42},
+		{"to offset clamped", model.ResetOffsetsRequest{Strategy: model.ResetToOffset, Offset: int64Ptr(500)}, 50, -1, 100},
+		{"shift back", model.ResetOffsetsRequest{Strategy: model.ResetShiftBy, Shift: int64Ptr(-20)}, 50, -1, 30},
+		{"shift clamped", model.ResetOffsetsRequest{Strategy: model.ResetShiftBy, Shift: int64Ptr(-100)}, 50, -1, 10},
+		{"shift without commit", model.ResetOffsetsRequest{Strategy: model.ResetShiftBy, Shift: int64Ptr(5)}, -1, -1, 15},
+		{"timestamp", model.ResetOffsetsRequest{Strategy: model.ResetToTimestamp}, 50, 70, 70},
+		{"timestamp past end", model.ResetOffsetsRequest{Strategy: model.ResetToDatetime}, 50, -1, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resetTarget(tt.req, tt.current, 10, 100, tt.atTime))
+		})
+	}
+}
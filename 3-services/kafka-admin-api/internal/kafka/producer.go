@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"kafka-admin-api/internal/model"
+)
+
+func newProducer(cfg Config, logger *slog.Logger) (*kafka.Producer, error) {
+	acks := cfg.ProducerAcks
+	if acks == "" {
+		acks = "all"
+	}
+
+	config := &kafka.ConfigMap{
+		"bootstrap.servers":  cfg.BootstrapServers,
+		"acks":               acks,
+		"enable.idempotence": cfg.ProducerIdempotence,
+	}
+	cfg.applySecurity(config)
+
+	producer, err := kafka.NewProducer(config)
+	if err != nil {
+		return nil, wrapError("create producer", err)
+	}
+
+	// Delivery reports are routed to per-request channels, so only
+	// client-level events such as broker errors arrive here.
+	go func() {
+		for e := range producer.Events() {
+			if ev, ok := e.(kafka.Error); ok {
+				logger.Error("producer error", "error", ev)
+			}
+		}
+	}()
+
+	logger.Info("kafka producer created", "acks", acks, "idempotence", cfg.ProducerIdempotence)
+	return producer, nil
+}
+
+// Produce sends messages to topic and waits until a delivery report has
+// arrived for each of them or ctx is done. Results are returned in the order
+// of messages; per-record failures are reported in ProduceResult.Error.
+func (c *Client) Produce(ctx context.Context, topic string, messages []model.ProduceMessage) (_ []model.ProduceResult, err error) {
+	defer c.observe("Produce", time.Now(), &err)
+
+	results := make([]model.ProduceResult, len(messages))
+	deliveryChan := make(chan kafka.Event, len(messages))
+
+	pending := 0
+	for i, m := range messages {
+		msg := &kafka.Message{
+			TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+			Opaque:         i,
+		}
+		if m.Key != nil {
+			msg.Key = []byte(*m.Key)
+		}
+		if m.Value != nil {
+			msg.Value = []byte(*m.Value)
+		}
+		if m.Partition != nil {
+			msg.TopicPartition.Partition = *m.Partition
+		}
+		if m.Timestamp > 0 {
+			msg.Timestamp = time.UnixMilli(m.Timestamp)
+		}
+		for k, v := range m.Headers {
+			msg.Headers = append(msg.Headers, kafka.Header{Key: k, Value: []byte(v)})
+		}
+
+		results[i].Partition = msg.TopicPartition.Partition
+		results[i].Offset = -1
+		if err := c.producer.Produce(msg, deliveryChan); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		pending++
+	}
+
+	for pending > 0 {
+		select {
+		case e := <-deliveryChan:
+			msg, ok := e.(*kafka.Message)
+			if !ok {
+				continue
+			}
+			pending--
+			i := msg.Opaque.(int)
+			results[i].Partition = msg.TopicPartition.Partition
+			if msg.TopicPartition.Error != nil {
+				results[i].Error = msg.TopicPartition.Error.Error()
+				continue
+			}
+			results[i].Offset = int64(msg.TopicPartition.Offset)
+			results[i].Timestamp = msg.Timestamp.UnixMilli()
+		case <-ctx.Done():
+			return results, fmt.Errorf("wait for delivery reports: %w", ctx.Err())
+		}
+	}
+
+	c.logger.Info("messages produced", "topic", topic, "count", len(messages))
+	return results, nil
+}
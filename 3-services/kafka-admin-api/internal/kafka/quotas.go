@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"kafka-admin-api/internal/model"
+)
+
+// Quota entity types, as the DescribeClientQuotas and AlterClientQuotas
+// APIs name them.
+const (
+	quotaEntityUser     = "user"
+	quotaEntityClientID = "client-id"
+)
+
+// DescribeClientQuotas returns the quotas of the entities matching filter,
+// where an empty name matches any entity. Entities with components the
+// model cannot express, such as IP quotas, are left out.
+func (c *Client) DescribeClientQuotas(ctx context.Context, filter model.QuotaEntity) (_ []model.ClientQuota, err error) {
+	defer c.observe("DescribeClientQuotas", time.Now(), &err)
+
+	described, err := c.franz.DescribeClientQuotas(ctx, false, quotaFilter(filter))
+	if err != nil {
+		return nil, franzError("describe client quotas", err)
+	}
+
+	quotas := make([]model.ClientQuota, 0, len(described))
+	for _, d := range described {
+		if quota, ok := clientQuota(d); ok {
+			quotas = append(quotas, quota)
+		}
+	}
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Entity.String() < quotas[j].Entity.String() })
+	return quotas, nil
+}
+
+// AlterClientQuotas sets the quotas in set and removes the keys in remove
+// for entity.
+func (c *Client) AlterClientQuotas(ctx context.Context, entity model.QuotaEntity, set map[string]float64, remove []string) (err error) {
+	defer c.observe("AlterClientQuotas", time.Now(), &err)
+
+	entry := kadm.AlterClientQuotaEntry{Entity: quotaEntity(entity)}
+	for key, value := range set {
+		entry.Ops = append(entry.Ops, kadm.AlterClientQuotaOp{Key: key, Value: value})
+	}
+	for _, key := range remove {
+		entry.Ops = append(entry.Ops, kadm.AlterClientQuotaOp{Key: key, Remove: true})
+	}
+
+	altered, err := c.franz.AlterClientQuotas(ctx, []kadm.AlterClientQuotaEntry{entry})
+	if err != nil {
+		return franzError("alter client quotas", err)
+	}
+	for _, a := range altered {
+		if a.Err != nil {
+			return franzError("alter client quotas", &kadm.ErrAndMessage{Err: a.Err, ErrMessage: a.ErrMessage})
+		}
+	}
+	return nil
+}
+
+// quotaFilter matches the named user and client ID exactly, or their
+// default entity for QuotaDefault.
+func quotaFilter(filter model.QuotaEntity) []kadm.DescribeClientQuotaComponent {
+	var components []kadm.DescribeClientQuotaComponent
+	add := func(entityType, name string) {
+		switch name {
+		case "":
+		case model.QuotaDefault:
+			components = append(components, kadm.DescribeClientQuotaComponent{
+				Type:      entityType,
+				MatchType: kmsg.QuotasMatchTypeDefault,
+			})
+		default:
+			components = append(components, kadm.DescribeClientQuotaComponent{
+				Type:      entityType,
+				MatchName: &name,
+				MatchType: kmsg.QuotasMatchTypeExact,
+			})
+		}
+	}
+	add(quotaEntityUser, filter.User)
+	add(quotaEntityClientID, filter.ClientID)
+	return components
+}
+
+// quotaEntity converts entity; a QuotaDefault name becomes the null name
+// Kafka uses for the default entity.
+func quotaEntity(entity model.QuotaEntity) kadm.ClientQuotaEntity {
+	var components kadm.ClientQuotaEntity
+	add := func(entityType, name string) {
+		if name == "" {
+			return
+		}
+		component := kadm.ClientQuotaEntityComponent{Type: entityType}
+		if name != model.QuotaDefault {
+			component.Name = &name
+		}
+		components = append(components, component)
+	}
+	add(quotaEntityUser, entity.User)
+	add(quotaEntityClientID, entity.ClientID)
+	return components
+}
+
+// clientQuota converts a described quota. It reports false for entities
+// with a component other than a user or client ID.
+func clientQuota(d kadm.DescribedClientQuota) (model.ClientQuota, bool) {
+	quota := model.ClientQuota{Quotas: make(map[string]float64, len(d.Values))}
+	for _, component := range d.Entity {
+		name := model.QuotaDefault
+		if component.Name != nil {
+			name = *component.Name
+		}
+		switch component.Type {
+		case quotaEntityUser:
+			quota.Entity.User = name
+		case quotaEntityClientID:
+			quota.Entity.ClientID = name
+		default:
+			return model.ClientQuota{}, false
+		}
+	}
+	for _, v := range d.Values {
+		quota.Quotas[v.Key] = v.Value
+	}
+	return quota, true
+}
@@ -0,0 +1,56 @@
+package kafka
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"kafka-admin-api/internal/model"
+)
+
+func TestQuotaEntityDefaults(t *testing.T) {
+	entity := quotaEntity(model.QuotaEntity{User: "billing-svc", ClientID: model.QuotaDefault})
+	require.Len(t, entity, 2)
+	assert.Equal(t, "user", entity[0].Type)
+	assert.Equal(t, "billing-svc", *entity[0].Name)
+	assert.Equal(t, "client-id", entity[1].Type)
+	assert.Nil(t, entity[1].Name, "the default entity has a null name")
+
+	filter := quotaFilter(model.QuotaEntity{User: model.QuotaDefault})
+	require.Len(t, filter, 1)
+	assert.Equal(t, kmsg.QuotasMatchTypeDefault, filter[0].MatchType)
+	assert.Empty(t, quotaFilter(model.QuotaEntity{}), "an empty filter matches every entity")
+}
+
+func TestClientQuota(t *testing.T) {
+	name := "batch"
+	quota, ok := clientQuota(kadm.DescribedClientQuota{
+		Entity: kadm.ClientQuotaEntity{{Type: "user"}, {Type: "client-id", Name: &name}},
+		Values: kadm.ClientQuotaValues{{Key: "producer_byte_rate", Value: 1048576}},
+	})
+	require.True(t, ok)
+	assert.Equal(t, model.QuotaEntity{User: model.QuotaDefault, ClientID: "batch"}, quota.Entity)
+	assert.Equal(t, map[string]float64{"producer_byte_rate": 1048576}, quota.Quotas)
+
+	ip := "10.0.0.1"
+	_, ok = clientQuota(kadm.DescribedClientQuota{Entity: kadm.ClientQuotaEntity{{Type: "ip", Name: &ip}}})
+	assert.False(t, ok, "IP quotas are left out")
+}
+
+func TestFranzErrorKeepsCode(t *testing.T) {
+	err := franzError("alter client quotas", &kadm.ErrAndMessage{Err: kerr.ClusterAuthorizationFailed})
+
+	var kafkaErr *Error
+	require.True(t, errors.As(err, &kafkaErr))
+	assert.Equal(t, kafka.ErrClusterAuthorizationFailed, kafkaErr.Code())
+	assert.Equal(t, "CLUSTER_AUTHORIZATION_FAILED", kafkaErr.Name())
+
+	plain := franzError("describe client quotas", errors.New("dial tcp: connection refused"))
+	assert.False(t, errors.As(plain, &kafkaErr))
+}
@@ -0,0 +1,86 @@
+package kafka
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"kafka-admin-api/internal/model"
+)
+
+// DescribeScramUsers returns the SCRAM credentials of users, or of every
+// user when users is empty. An unknown user is a RESOURCE_NOT_FOUND error.
+func (c *Client) DescribeScramUsers(ctx context.Context, users []string) (_ []model.ScramUser, err error) {
+	defer c.observe("DescribeScramUsers", time.Now(), &err)
+
+	result, err := c.admin.DescribeUserScramCredentials(ctx, users)
+	if err != nil {
+		return nil, wrapError("describe user scram credentials", err)
+	}
+
+	described := make([]model.ScramUser, 0, len(result.Descriptions))
+	for name, d := range result.Descriptions {
+		if d.Error.Code() != kafka.ErrNoError {
+			return nil, newError("describe user scram credentials "+name, d.Error)
+		}
+		user := model.ScramUser{User: name, Credentials: make([]model.ScramCredential, 0, len(d.ScramCredentialInfos))}
+		for _, info := range d.ScramCredentialInfos {
+			user.Credentials = append(user.Credentials, model.ScramCredential{
+				Mechanism:  info.Mechanism.String(),
+				Iterations: info.Iterations,
+			})
+		}
+		described = append(described, user)
+	}
+	sort.Slice(described, func(i, j int) bool { return described[i].User < described[j].User })
+	return described, nil
+}
+
+// UpsertScramCredential creates or replaces the user's credential for
+// mechanism. The broker salts and hashes password; it is not stored here.
+func (c *Client) UpsertScramCredential(ctx context.Context, user, mechanism string, iterations int, password []byte) (err error) {
+	defer c.observe("UpsertScramCredential", time.Now(), &err)
+
+	m, err := kafka.ScramMechanismFromString(mechanism)
+	if err != nil {
+		return errorf(kafka.ErrInvalidArg, "unknown SCRAM mechanism %q", mechanism)
+	}
+	upsertion := kafka.UserScramCredentialUpsertion{
+		User:                user,
+		ScramCredentialInfo: kafka.ScramCredentialInfo{Mechanism: m, Iterations: iterations},
+		Password:            password,
+	}
+	return c.alterScramCredentials(ctx, []kafka.UserScramCredentialUpsertion{upsertion}, nil)
+}
+
+// DeleteScramCredentials deletes the user's credentials for mechanisms.
+func (c *Client) DeleteScramCredentials(ctx context.Context, user string, mechanisms []string) (err error) {
+	defer c.observe("DeleteScramCredentials", time.Now(), &err)
+
+	deletions := make([]kafka.UserScramCredentialDeletion, 0, len(mechanisms))
+	for _, mechanism := range mechanisms {
+		m, err := kafka.ScramMechanismFromString(mechanism)
+		if err != nil {
+			return errorf(kafka.ErrInvalidArg, "unknown SCRAM mechanism %q", mechanism)
+		}
+		deletions = append(deletions, kafka.UserScramCredentialDeletion{User: user, Mechanism: m})
+	}
+	return c.alterScramCredentials(ctx, nil, deletions)
+}
+
+func (c *Client) alterScramCredentials(ctx context.Context, upsertions []kafka.UserScramCredentialUpsertion, deletions []kafka.UserScramCredentialDeletion) error {
+	result, err := c.admin.AlterUserScramCredentials(ctx, upsertions, deletions)
+	if err != nil {
+		return wrapError("alter user scram credentials", err)
+	}
+	for user, kerr := range result.Errors {
+		if kerr.Code() != kafka.ErrNoError {
+			return newError("alter user scram credentials "+user, kerr)
+		}
+	}
+
+	c.logger.Info("scram credentials altered", "upserted", len(upsertions), "deleted", len(deletions))
+	return nil
+}
@@ -0,0 +1,187 @@
+package lag
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"kafka-admin-api/internal/model"
+)
+
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+type sample struct {
+	offset int64
+	lag    int64
+}
+
+type groupWindow struct {
+	state      string
+	updated    time.Time
+	partitions map[partitionKey][]sample
+}
+
+// Evaluator keeps a sliding window of committed offset and lag samples per
+// group partition and classifies consumer progress in the spirit of
+// Burrow's evaluation rules.
+type Evaluator struct {
+	window int
+
+	mu     sync.RWMutex
+	groups map[string]*groupWindow
+}
+
+// NewEvaluator keeps the last window samples of every partition.
+func NewEvaluator(window int) *Evaluator {
+	if window < 2 {
+		window = 2
+	}
+	return &Evaluator{
+		window: window,
+		groups: make(map[string]*groupWindow),
+	}
+}
+
+// Record adds one sample for each partition of groupID. Partitions missing
+// from partitions are forgotten.
+func (e *Evaluator) Record(groupID, state string, partitions []model.TopicPartition, at time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	g, ok := e.groups[groupID]
+	if !ok {
+		g = &groupWindow{partitions: make(map[partitionKey][]sample)}
+		e.groups[groupID] = g
+	}
+	g.state = state
+	g.updated = at
+
+	next := make(map[partitionKey][]sample, len(partitions))
+	for _, p := range partitions {
+		key := partitionKey{topic: p.Topic, partition: p.Partition}
+		samples := append(g.partitions[key], sample{offset: p.Offset, lag: p.Lag})
+		if len(samples) > e.window {
+			samples = samples[len(samples)-e.window:]
+		}
+		next[key] = samples
+	}
+	g.partitions = next
+}
+
+// Retain forgets every group not in groupIDs, e.g. deleted groups.
+func (e *Evaluator) Retain(groupIDs map[string]bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for id := range e.groups {
+		if !groupIDs[id] {
+			delete(e.groups, id)
+		}
+	}
+}
+
+// Status returns the overall status of groupID, or false when the group has
+// not been sampled.
+func (e *Evaluator) Status(groupID string) (model.LagStatus, bool) {
+	health, ok := e.GroupHealth(groupID)
+	if !ok {
+		return "", false
+	}
+	return health.Status, true
+}
+
+// GroupHealth evaluates every partition of groupID. It returns false when
+// the group has not been sampled.
+func (e *Evaluator) GroupHealth(groupID string) (*model.ConsumerGroupHealth, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	g, ok := e.groups[groupID]
+	if !ok {
+		return nil, false
+	}
+
+	health := &model.ConsumerGroupHealth{
+		GroupID:     groupID,
+		Status:      model.LagOK,
+		EvaluatedAt: g.updated,
+		Partitions:  make([]model.PartitionHealth, 0, len(g.partitions)),
+	}
+	for key, samples := range g.partitions {
+		last := samples[len(samples)-1]
+		status := evaluate(samples, g.state, e.window)
+		health.Partitions = append(health.Partitions, model.PartitionHealth{
+			Topic:     key.topic,
+			Partition: key.partition,
+			Status:    status,
+			Offset:    last.offset,
+			Lag:       last.lag,
+			Samples:   len(samples),
+		})
+		health.TotalLag += last.lag
+		if severity[status] > severity[health.Status] {
+			health.Status = status
+		}
+	}
+	sort.Slice(health.Partitions, func(i, j int) bool {
+		a, b := health.Partitions[i], health.Partitions[j]
+		if a.Topic != b.Topic {
+			return a.Topic < b.Topic
+		}
+		return a.Partition < b.Partition
+	})
+	return health, true
+}
+
+// severity orders statuses so a group reports its worst partition.
+var severity = map[model.LagStatus]int{
+	model.LagOK:        0,
+	model.LagWarning:   1,
+	model.LagRewinding: 2,
+	model.LagStalled:   3,
+	model.LagStopped:   4,
+}
+
+// evaluate classifies one partition window, oldest sample first:
+//   - REWINDING: the committed offset moved backwards within the window.
+//   - OK: the consumer is caught up.
+//   - STOPPED: lag remains but the group has no active members.
+//   - STALLED: members are present but the offset did not move across a
+//     full window of window samples while lag remained.
+//   - WARNING: lag never decreased and grew across the window.
+func evaluate(samples []sample, state string, window int) model.LagStatus {
+	for i := 1; i < len(samples); i++ {
+		if samples[i].offset < samples[i-1].offset {
+			return model.LagRewinding
+		}
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	if last.lag == 0 {
+		return model.LagOK
+	}
+	if state == "Empty" || state == "Dead" {
+		return model.LagStopped
+	}
+	if len(samples) < 2 {
+		return model.LagOK
+	}
+	// A pause shorter than the window, e.g. one missed scrape, is not a
+	// stall.
+	if len(samples) == window && first.offset == last.offset {
+		return model.LagStalled
+	}
+
+	for i := 1; i < len(samples); i++ {
+		if samples[i].lag < samples[i-1].lag {
+			return model.LagOK
+		}
+	}
+	if last.lag > first.lag {
+		return model.LagWarning
+	}
+	return model.LagOK
+}
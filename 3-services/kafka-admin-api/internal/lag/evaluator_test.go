@@ -0,0 +1,68 @@
+package lag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"kafka-admin-api/internal/model"
+)
+
+func samples(pairs ...int64) []sample {
+	out := make([]sample, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		out = append(out, sample{offset: pairs[i], lag: pairs[i+1]})
+	}
+	return out
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []sample
+		state    string
+		expected model.LagStatus
+	}{
+		{"caught up", samples(100, 0, 110, 0, 120, 0), "Stable", model.LagOK},
+		{"keeping up", samples(100, 20, 110, 15, 120, 18), "Stable", model.LagOK},
+		{"single sample", samples(100, 20), "Stable", model.LagOK},
+		{"lag growing", samples(100, 10, 105, 15, 110, 30), "Stable", model.LagWarning},
+		{"lag flat", samples(100, 10, 105, 10, 110, 10), "Stable", model.LagOK},
+		{"stalled", samples(100, 10, 100, 12, 100, 14), "Stable", model.LagStalled},
+		{"paused within window", samples(100, 10, 100, 10), "Stable", model.LagOK},
+		{"stopped", samples(100, 10, 100, 12), "Empty", model.LagStopped},
+		{"stopped but caught up", samples(100, 0, 100, 0), "Empty", model.LagOK},
+		{"rewinding", samples(100, 10, 50, 60, 60, 50), "Stable", model.LagRewinding},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, evaluate(tt.samples, tt.state, 3))
+		})
+	}
+}
+
+func TestEvaluatorWindow(t *testing.T) {
+	e := NewEvaluator(3)
+	now := time.Now()
+
+	// A rewind ages out of the window after three newer samples.
+	for _, offset := range []int64{100, 50, 60, 70, 80} {
+		e.Record("group-1", "Stable", []model.TopicPartition{
+			{Topic: "orders", Partition: 0, Offset: offset, Lag: 5},
+			{Topic: "orders", Partition: 1, Offset: 10, Lag: 0},
+		}, now)
+	}
+
+	health, ok := e.GroupHealth("group-1")
+	assert.True(t, ok)
+	assert.Equal(t, model.LagOK, health.Status)
+	assert.Equal(t, int64(5), health.TotalLag)
+	assert.Equal(t, 3, health.Partitions[0].Samples)
+	assert.Equal(t, int32(1), health.Partitions[1].Partition)
+
+	e.Retain(map[string]bool{})
+	_, ok = e.GroupHealth("group-1")
+	assert.False(t, ok)
+}
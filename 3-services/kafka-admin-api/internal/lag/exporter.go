@@ -0,0 +1,194 @@
+package lag
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"kafka-admin-api/internal/model"
+)
+
+// Source is the subset of the Kafka client the exporter needs.
+type Source interface {
+	ListConsumerGroups(ctx context.Context) ([]model.ConsumerGroup, error)
+	ConsumerGroupLag(ctx context.Context, groupID string) ([]model.TopicPartition, error)
+}
+
+type Config struct {
+	Interval time.Duration
+	// Include and Exclude filter groups by ID. A nil Include matches every
+	// group; Exclude wins over Include.
+	Include *regexp.Regexp
+	Exclude *regexp.Regexp
+	// Concurrency bounds how many groups are queried at the same time.
+	Concurrency int
+	// Evaluator, when set, receives every successful sample.
+	Evaluator *Evaluator
+}
+
+var (
+	lagDesc = prometheus.NewDesc(
+		"kafka_consumergroup_lag",
+		"Difference between the high watermark and the committed offset of a partition.",
+		[]string{"group", "topic", "partition"}, nil,
+	)
+	offsetDesc = prometheus.NewDesc(
+		"kafka_consumergroup_current_offset",
+		"Committed offset of a consumer group on a partition.",
+		[]string{"group", "topic", "partition"}, nil,
+	)
+	highWatermarkDesc = prometheus.NewDesc(
+		"kafka_consumergroup_high_watermark",
+		"High watermark of a partition a consumer group has committed for.",
+		[]string{"group", "topic", "partition"}, nil,
+	)
+	lastCollectDesc = prometheus.NewDesc(
+		"kafka_consumergroup_lag_last_collect_timestamp_seconds",
+		"Unix time of the last completed lag collection.",
+		nil, nil,
+	)
+)
+
+// Exporter periodically collects consumer group lag in the background and
+// serves the latest snapshot as a prometheus.Collector, so scrapes never
+// block on Kafka.
+type Exporter struct {
+	source Source
+	config Config
+	logger *slog.Logger
+
+	mu          sync.RWMutex
+	groups      map[string][]model.TopicPartition
+	lastCollect time.Time
+}
+
+func NewExporter(source Source, cfg Config, logger *slog.Logger) *Exporter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	return &Exporter{
+		source: source,
+		config: cfg,
+		logger: logger,
+		groups: make(map[string][]model.TopicPartition),
+	}
+}
+
+// Run collects once immediately and then every Interval until ctx is done.
+func (e *Exporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		e.collect(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Exporter) collect(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, e.config.Interval)
+	defer cancel()
+
+	groups, err := e.source.ListConsumerGroups(ctx)
+	if err != nil {
+		e.logger.Error("lag exporter: list consumer groups", "error", err)
+		return
+	}
+
+	e.mu.RLock()
+	previous := e.groups
+	e.mu.RUnlock()
+
+	now := time.Now()
+	matched := make(map[string]bool, len(groups))
+
+	var (
+		wg       sync.WaitGroup
+		resultMu sync.Mutex
+		sem      = make(chan struct{}, e.config.Concurrency)
+		next     = make(map[string][]model.TopicPartition, len(groups))
+	)
+	for _, g := range groups {
+		if !e.matches(g.GroupID) {
+			continue
+		}
+		matched[g.GroupID] = true
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(groupID, state string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			partitions, err := e.source.ConsumerGroupLag(ctx, groupID)
+			if err != nil {
+				// Keep the last known values rather than dropping the
+				// series on a transient failure.
+				e.logger.Warn("lag exporter: collect group", "group_id", groupID, "error", err)
+				partitions = previous[groupID]
+			} else if e.config.Evaluator != nil {
+				e.config.Evaluator.Record(groupID, state, partitions, now)
+			}
+
+			resultMu.Lock()
+			next[groupID] = partitions
+			resultMu.Unlock()
+		}(g.GroupID, g.State)
+	}
+	wg.Wait()
+
+	if e.config.Evaluator != nil {
+		e.config.Evaluator.Retain(matched)
+	}
+
+	e.mu.Lock()
+	e.groups = next
+	e.lastCollect = now
+	e.mu.Unlock()
+}
+
+func (e *Exporter) matches(groupID string) bool {
+	if e.config.Exclude != nil && e.config.Exclude.MatchString(groupID) {
+		return false
+	}
+	return e.config.Include == nil || e.config.Include.MatchString(groupID)
+}
+
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- lagDesc
+	ch <- offsetDesc
+	ch <- highWatermarkDesc
+	ch <- lastCollectDesc
+}
+
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for group, partitions := range e.groups {
+		for _, p := range partitions {
+			labels := []string{group, p.Topic, strconv.Itoa(int(p.Partition))}
+			ch <- prometheus.MustNewConstMetric(lagDesc, prometheus.GaugeValue, float64(p.Lag), labels...)
+			ch <- prometheus.MustNewConstMetric(offsetDesc, prometheus.GaugeValue, float64(p.Offset), labels...)
+			ch <- prometheus.MustNewConstMetric(highWatermarkDesc, prometheus.GaugeValue, float64(p.LogEndOffset), labels...)
+		}
+	}
+	if !e.lastCollect.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastCollectDesc, prometheus.GaugeValue,
+			float64(e.lastCollect.UnixNano())/1e9)
+	}
+}
@@ -0,0 +1,124 @@
+package lag
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"kafka-admin-api/internal/model"
+)
+
+type fakeSource struct {
+	groups []model.ConsumerGroup
+	lags   map[string][]model.TopicPartition
+	failed map[string]bool
+
+	mu       sync.Mutex
+	calls    []string
+	inFlight int32
+	peak     int32
+}
+
+func (f *fakeSource) ListConsumerGroups(context.Context) ([]model.ConsumerGroup, error) {
+	return f.groups, nil
+}
+
+func (f *fakeSource) ConsumerGroupLag(_ context.Context, groupID string) ([]model.TopicPartition, error) {
+	n := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	f.mu.Lock()
+	f.calls = append(f.calls, groupID)
+	f.mu.Unlock()
+
+	if f.failed[groupID] {
+		return nil, errors.New("coordinator not available")
+	}
+	return f.lags[groupID], nil
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{
+		groups: []model.ConsumerGroup{
+			{GroupID: "orders-service"},
+			{GroupID: "payments-service"},
+			{GroupID: "connect-sink"},
+			{GroupID: "orders-service-test"},
+		},
+		lags: map[string][]model.TopicPartition{
+			"orders-service": {
+				{Topic: "orders", Partition: 0, Offset: 90, LogEndOffset: 100, Lag: 10},
+			},
+			"payments-service": {
+				{Topic: "payments", Partition: 1, Offset: 5, LogEndOffset: 7, Lag: 2},
+			},
+		},
+		failed: map[string]bool{},
+	}
+}
+
+func TestExporterCollect(t *testing.T) {
+	source := newFakeSource()
+	exporter := NewExporter(source, Config{
+		Include:     regexp.MustCompile(`-service`),
+		Exclude:     regexp.MustCompile(`-test$`),
+		Concurrency: 2,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	exporter.collect(context.Background())
+
+	assert.ElementsMatch(t, []string{"orders-service", "payments-service"}, source.calls)
+
+	expected := `
+# HELP kafka_consumergroup_lag Difference between the high watermark and the committed offset of a partition.
+# TYPE kafka_consumergroup_lag gauge
+kafka_consumergroup_lag{group="orders-service",partition="0",topic="orders"} 10
+kafka_consumergroup_lag{group="payments-service",partition="1",topic="payments"} 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(exporter, strings.NewReader(expected), "kafka_consumergroup_lag"))
+}
+
+func TestExporterKeepsLastValuesOnFailure(t *testing.T) {
+	source := newFakeSource()
+	exporter := NewExporter(source, Config{Concurrency: 4}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	exporter.collect(context.Background())
+	source.failed["orders-service"] = true
+	source.groups = source.groups[:1]
+	exporter.collect(context.Background())
+
+	// The failed group keeps its series while groups that disappeared are
+	// dropped.
+	assert.Len(t, exporter.groups, 1)
+	assert.Equal(t, int64(10), exporter.groups["orders-service"][0].Lag)
+}
+
+func TestExporterBoundsConcurrency(t *testing.T) {
+	source := newFakeSource()
+	for i := 0; i < 20; i++ {
+		source.groups = append(source.groups, model.ConsumerGroup{GroupID: "group-" + string(rune('a'+i))})
+	}
+	exporter := NewExporter(source, Config{Concurrency: 3}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	exporter.collect(context.Background())
+
+	assert.Len(t, source.calls, len(source.groups))
+	assert.LessOrEqual(t, atomic.LoadInt32(&source.peak), int32(3))
+}
@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "kafka_admin_api"
+
+// Metrics holds the service's own Prometheus collectors on a dedicated
+// registry, so /metrics exposes only what this service registers plus the
+// standard Go and process collectors.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	kafkaCalls       *prometheus.HistogramVec
+	kafkaErrors      *prometheus.CounterVec
+	sseConsumers     prometheus.Gauge
+	messagesStreamed prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		kafkaCalls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "kafka_call_duration_seconds",
+			Help:      "Latency of Kafka client calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		kafkaErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "kafka_call_errors_total",
+			Help:      "Failed Kafka client calls by method.",
+		}, []string{"method"}),
+		sseConsumers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sse_consumers",
+			Help:      "Number of live SSE message streams.",
+		}),
+		messagesStreamed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sse_messages_streamed_total",
+			Help:      "Messages sent to SSE clients.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.kafkaCalls,
+		m.kafkaErrors,
+		m.sseConsumers,
+		m.messagesStreamed,
+	)
+	return m
+}
+
+// Registry exposes the registry so other components can add collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Middleware records request counts and latency. Requests are labelled
+// with the matched route pattern rather than the raw path to keep label
+// cardinality bounded.
+func (m *Metrics) Middleware(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		var fe *fiber.Error
+		if errors.As(err, &fe) {
+			status = fe.Code
+		}
+	}
+
+	route := c.Route().Path
+	if status == fiber.StatusNotFound && route == "/" {
+		route = "unmatched"
+	}
+
+	labels := prometheus.Labels{
+		"method": c.Method(),
+		"route":  route,
+		"status": strconv.Itoa(status),
+	}
+	m.httpRequests.With(labels).Inc()
+	m.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	return err
+}
+
+// ObserveKafkaCall records the latency and outcome of a Kafka client call.
+func (m *Metrics) ObserveKafkaCall(method string, duration time.Duration, err error) {
+	m.kafkaCalls.WithLabelValues(method).Observe(duration.Seconds())
+	if err != nil {
+		m.kafkaErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// SSEConsumerStarted, SSEConsumerStopped and MessageStreamed are safe to
+// call on a nil *Metrics so handlers need not check whether metrics are
+// enabled.
+func (m *Metrics) SSEConsumerStarted() {
+	if m != nil {
+		m.sseConsumers.Inc()
+	}
+}
+
+func (m *Metrics) SSEConsumerStopped() {
+	if m != nil {
+		m.sseConsumers.Dec()
+	}
+}
+
+func (m *Metrics) MessageStreamed() {
+	if m != nil {
+		m.messagesStreamed.Inc()
+	}
+}
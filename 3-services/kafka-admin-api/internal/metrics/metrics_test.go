@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveKafkaCall(t *testing.T) {
+	m := New()
+
+	m.ObserveKafkaCall("ListTopics", 20*time.Millisecond, nil)
+	m.ObserveKafkaCall("ListTopics", 30*time.Millisecond, errors.New("timed out"))
+
+	assert.Equal(t, 1, testutil.CollectAndCount(m.kafkaCalls))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.kafkaErrors.WithLabelValues("ListTopics")))
+}
+
+func TestSSEHelpersOnNil(t *testing.T) {
+	var m *Metrics
+	assert.NotPanics(t, func() {
+		m.SSEConsumerStarted()
+		m.MessageStreamed()
+		m.SSEConsumerStopped()
+	})
+}
@@ -1,21 +1,62 @@
 package model
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
 type Broker struct {
 	ID   int32  `json:"id"`
 	Host string `json:"host"`
 	Port int32  `json:"port"`
 }
 
+// Cluster describes the brokers of the cluster and its active controller.
+// ControllerID is -1 when no controller is known.
+type Cluster struct {
+	ClusterID    string   `json:"cluster_id"`
+	ControllerID int32    `json:"controller_id"`
+	Brokers      []Broker `json:"brokers"`
+}
+
+// Readiness check statuses. A degraded check is reported but does not make
+// the service unready.
+const (
+	CheckOK       = "ok"
+	CheckDegraded = "degraded"
+	CheckFailed   = "failed"
+)
+
+type ReadinessCheck struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+type Readiness struct {
+	Status          string           `json:"status"`
+	Brokers         int              `json:"brokers"`
+	ExpectedBrokers int              `json:"expected_brokers,omitempty"`
+	ControllerID    int32            `json:"controller_id"`
+	Checks          []ReadinessCheck `json:"checks"`
+}
+
 type Topic struct {
 	Name              string `json:"name"`
 	PartitionCount    int    `json:"partition_count"`
 	ReplicationFactor int    `json:"replication_factor"`
 }
 
+// TopicDetail lists the configs set on the topic itself in Configs.
+// ConfigEntries describes every config, including defaults, and is only
+// filled in for ?include=all.
 type TopicDetail struct {
-	Name       string            `json:"name"`
-	Partitions []Partition       `json:"partitions"`
-	Configs    map[string]string `json:"configs"`
+	Name          string            `json:"name"`
+	Partitions    []Partition       `json:"partitions"`
+	Configs       map[string]string `json:"configs"`
+	ConfigEntries []ConfigEntry     `json:"config_entries,omitempty"`
 }
 
 type Partition struct {
@@ -32,21 +73,165 @@ type CreateTopicRequest struct {
 	Configs           map[string]string `json:"configs,omitempty"`
 }
 
+const (
+	ConfigSourceDynamicTopic   = "dynamic-topic"
+	ConfigSourceDynamicBroker  = "dynamic-broker"
+	ConfigSourceDynamicDefault = "dynamic-default"
+	ConfigSourceStatic         = "static"
+	ConfigSourceDefault        = "default"
+	ConfigSourceUnknown        = "unknown"
+)
+
+// ConfigEntry is a described config. Value is null for sensitive configs,
+// which Kafka never returns. Source tells where the value is set:
+// dynamic-topic, dynamic-broker, dynamic-default (cluster-wide),
+// static (server.properties) or default. Synonyms lists the levels the
+// config is set at, highest precedence first. The client library keys
+// synonyms by name, so a name set at several levels, e.g. log.retention.ms
+// set dynamically and in server.properties, appears only once with one of
+// them. The first synonym is then not necessarily in effect; Value and
+// Source are.
+type ConfigEntry struct {
+	Name      string          `json:"name"`
+	Value     *string         `json:"value"`
+	Source    string          `json:"source"`
+	ReadOnly  bool            `json:"read_only"`
+	Sensitive bool            `json:"sensitive"`
+	Synonyms  []ConfigSynonym `json:"synonyms,omitempty"`
+}
+
+// ConfigSynonym is one level a config is set at. Broker-level synonyms may
+// use a different name, e.g. log.retention.ms for a topic's retention.ms.
+type ConfigSynonym struct {
+	Name   string  `json:"name"`
+	Value  *string `json:"value"`
+	Source string  `json:"source"`
+}
+
+type UpdateConfigsRequest struct {
+	Configs map[string]string `json:"configs" validate:"required,min=1"`
+}
+
+// TopicsDocument is the desired state of the cluster's topics, as kept in
+// git. Configs not listed for a topic are left as they are.
+type TopicsDocument struct {
+	Topics []TopicSpec `json:"topics" yaml:"topics" validate:"dive"`
+	// Prune deletes topics that exist in the cluster but are not listed.
+	// A topic with active consumer groups is skipped unless it is named in
+	// PruneForce.
+	Prune      bool     `json:"prune" yaml:"prune"`
+	PruneForce []string `json:"prune_force,omitempty" yaml:"prune_force"`
+}
+
+type TopicSpec struct {
+	Name              string            `json:"name" yaml:"name" validate:"required,min=1"`
+	Partitions        int32             `json:"partitions" yaml:"partitions" validate:"required,min=1"`
+	ReplicationFactor int16             `json:"replication_factor" yaml:"replication_factor" validate:"required,min=1,max=3"`
+	Configs           map[string]string `json:"configs,omitempty" yaml:"configs"`
+}
+
+const (
+	ChangeCreate        = "create"
+	ChangeUpdateConfigs = "update-configs"
+	ChangeAddPartitions = "add-partitions"
+	ChangeDelete        = "delete"
+)
+
+type ConfigChange struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to"`
+}
+
+type PartitionChange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+// TopicChange is one step of a TopicPlan.
+type TopicChange struct {
+	Action            string                  `json:"action"`
+	Topic             string                  `json:"topic"`
+	Partitions        *PartitionChange        `json:"partitions,omitempty"`
+	ReplicationFactor int16                   `json:"replication_factor,omitempty"`
+	Configs           map[string]ConfigChange `json:"configs,omitempty"`
+	// Force deletes the topic even while consumer groups are active.
+	Force bool `json:"force,omitempty"`
+}
+
+// TopicPlan lists the changes that bring the cluster to a TopicsDocument.
+// Warnings report differences that cannot be applied, such as a different
+// replication factor or fewer partitions. Forbidden names listed topics
+// that exist but that the caller may not describe; they are left as they
+// are.
+type TopicPlan struct {
+	Changes   []TopicChange `json:"changes"`
+	Warnings  []string      `json:"warnings,omitempty"`
+	Forbidden []string      `json:"forbidden,omitempty"`
+}
+
+const (
+	ChangeApplied = "applied"
+	ChangeFailed  = "failed"
+	ChangeSkipped = "skipped"
+)
+
+// TopicChangeResult is the outcome of one change. Code is the error code
+// of a failed change, as in error responses, e.g. TOPIC_POLICY_VIOLATION.
+type TopicChangeResult struct {
+	TopicChange
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Code   string `json:"code,omitempty"`
+}
+
+type TopicApplyResponse struct {
+	Results   []TopicChangeResult `json:"results"`
+	Warnings  []string            `json:"warnings,omitempty"`
+	Forbidden []string            `json:"forbidden,omitempty"`
+}
+
+// PolicyViolation is one reason a topic request was rejected by the topic
+// policy. Field names the offending request field, e.g. partitions or
+// configs.retention.ms.
+type PolicyViolation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type UpdateTopicRequest struct {
 	Configs map[string]string `json:"configs" validate:"required,min=1"`
 }
 
+type CreatePartitionsRequest struct {
+	Count int32 `json:"count" validate:"required,min=1"`
+	// ReplicaAssignment optionally lists the replica broker IDs for each
+	// new partition, indexed from the first added partition.
+	ReplicaAssignment [][]int32 `json:"replica_assignment,omitempty"`
+}
+
+type CreatePartitionsResponse struct {
+	Message        string   `json:"message"`
+	PreviousCount  int      `json:"previous_count"`
+	PartitionCount int      `json:"partition_count"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
 type ConsumerGroup struct {
 	GroupID      string `json:"group_id"`
 	State        string `json:"state"`
 	ProtocolType string `json:"protocol_type"`
+	// Health is the lag evaluation status, empty until the group has been
+	// sampled by the lag evaluator.
+	Health LagStatus `json:"health,omitempty"`
 }
 
 type ConsumerGroupDetail struct {
-	GroupID     string   `json:"group_id"`
-	State       string   `json:"state"`
-	Coordinator Broker   `json:"coordinator"`
-	Members     []Member `json:"members"`
+	GroupID     string     `json:"group_id"`
+	State       string     `json:"state"`
+	Coordinator Broker     `json:"coordinator"`
+	Members     []Member   `json:"members"`
+	Topics      []TopicLag `json:"topics"`
+	TotalLag    int64      `json:"total_lag"`
 }
 
 type Member struct {
@@ -57,21 +242,173 @@ type Member struct {
 }
 
 type TopicPartition struct {
+	Topic        string `json:"topic"`
+	Partition    int32  `json:"partition"`
+	Offset       int64  `json:"offset"`
+	LogEndOffset int64  `json:"log_end_offset"`
+	Lag          int64  `json:"lag"`
+}
+
+// TopicLag rolls up committed-offset lag for one topic of a consumer group.
+type TopicLag struct {
+	Topic      string           `json:"topic"`
+	Lag        int64            `json:"lag"`
+	Partitions []TopicPartition `json:"partitions"`
+}
+
+// LagStatus classifies a consumer's progress over the evaluation window.
+type LagStatus string
+
+const (
+	LagOK        LagStatus = "OK"
+	LagWarning   LagStatus = "WARNING"
+	LagRewinding LagStatus = "REWINDING"
+	LagStalled   LagStatus = "STALLED"
+	LagStopped   LagStatus = "STOPPED"
+)
+
+type PartitionHealth struct {
+	Topic     string    `json:"topic"`
+	Partition int32     `json:"partition"`
+	Status    LagStatus `json:"status"`
+	Offset    int64     `json:"offset"`
+	Lag       int64     `json:"lag"`
+	// Samples is the number of samples the status is based on.
+	Samples int `json:"samples"`
+}
+
+// ConsumerGroupHealth is the worst partition status of a group together
+// with the per-partition breakdown.
+type ConsumerGroupHealth struct {
+	GroupID     string            `json:"group_id"`
+	Status      LagStatus         `json:"status"`
+	TotalLag    int64             `json:"total_lag"`
+	EvaluatedAt time.Time         `json:"evaluated_at"`
+	Partitions  []PartitionHealth `json:"partitions"`
+}
+
+// ConsumerGroupResult reports the outcome of a delete operation for one
+// group, or for one topic's offsets within a group when Topic is set.
+type ConsumerGroupResult struct {
+	GroupID string `json:"group_id"`
+	Topic   string `json:"topic,omitempty"`
+	Deleted bool   `json:"deleted"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Offset reset strategies, mirroring kafka-consumer-groups --reset-offsets.
+const (
+	ResetToEarliest  = "to-earliest"
+	ResetToLatest    = "to-latest"
+	ResetToOffset    = "to-offset"
+	ResetToTimestamp = "to-timestamp"
+	ResetToDatetime  = "to-datetime"
+	ResetShiftBy     = "shift-by"
+)
+
+// ResetOffsetsRequest resets a group's offsets. Offset, Timestamp,
+// Datetime and Shift are required by the strategy they belong to; they are
+// pointers so that 0 is a valid value rather than a missing one.
+type ResetOffsetsRequest struct {
+	Strategy string `json:"strategy" validate:"required,oneof=to-earliest to-latest to-offset to-timestamp to-datetime shift-by"`
+	// Topic limits the reset to one topic; empty means every topic the
+	// group has committed offsets for.
+	Topic      string  `json:"topic,omitempty"`
+	Partitions []int32 `json:"partitions,omitempty"`
+	Offset     *int64  `json:"offset,omitempty" validate:"required_if=Strategy to-offset"`
+	Timestamp  *int64  `json:"timestamp,omitempty" validate:"required_if=Strategy to-timestamp"` // unix millis
+	Datetime   string  `json:"datetime,omitempty" validate:"required_if=Strategy to-datetime"`   // RFC 3339
+	Shift      *int64  `json:"shift,omitempty" validate:"required_if=Strategy shift-by"`         // may be negative
+	DryRun     bool    `json:"dry_run"`
+}
+
+type OffsetReset struct {
+	Topic          string `json:"topic"`
+	Partition      int32  `json:"partition"`
+	PreviousOffset int64  `json:"previous_offset"`
+	NewOffset      int64  `json:"new_offset"`
+}
+
+type ResetOffsetsResponse struct {
+	GroupID    string        `json:"group_id"`
+	DryRun     bool          `json:"dry_run"`
+	Partitions []OffsetReset `json:"partitions"`
+}
+
+// Consumer Message
+type Message struct {
 	Topic     string `json:"topic"`
 	Partition int32  `json:"partition"`
 	Offset    int64  `json:"offset"`
-	Lag       int64  `json:"lag"`
+	Key       string `json:"key,omitempty"`
+	// Value is a JSON string in the reported Encoding, or embedded JSON
+	// when Encoding is "json".
+	Value    json.RawMessage `json:"value"`
+	Encoding string          `json:"encoding"`
+	// KeyEncoding and HeadersEncoding are set when the record has a key or
+	// headers. They differ from Encoding when the value is rendered as
+	// JSON but the key or a header is not valid UTF-8.
+	KeyEncoding     string            `json:"key_encoding,omitempty"`
+	Timestamp       int64             `json:"timestamp"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	HeadersEncoding string            `json:"headers_encoding,omitempty"`
+	// Set when the value was decoded through the Schema Registry.
+	SchemaID    int    `json:"schema_id,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	SchemaError string `json:"schema_error,omitempty"`
 }
 
-// Consumer Message
-type Message struct {
-	Topic     string            `json:"topic"`
-	Partition int32             `json:"partition"`
-	Offset    int64             `json:"offset"`
-	Key       string            `json:"key,omitempty"`
-	Value     string            `json:"value"`
-	Timestamp int64             `json:"timestamp"`
+// BrowseRequest selects a page of messages to read without a consumer
+// group. At most one of Cursor, Last, Timestamp and Offset is set; with none
+// of them reading starts at the earliest offset.
+type BrowseRequest struct {
+	Partitions []int32
+	Offset     *int64
+	Timestamp  *int64
+	Last       int
+	Limit      int
+	Cursor     map[int32]int64
+}
+
+type BrowsePage struct {
+	Topic       string          `json:"topic"`
+	Count       int             `json:"count"`
+	Messages    []Message       `json:"messages"`
+	NextOffsets map[int32]int64 `json:"next_offsets"`
+	NextCursor  string          `json:"next_cursor"`
+	HasMore     bool            `json:"has_more"`
+}
+
+// ProduceMessage is a single record to produce. A nil Value produces a
+// tombstone; Partition and Timestamp (unix millis) are optional.
+type ProduceMessage struct {
+	Key       *string           `json:"key,omitempty"`
+	Value     *string           `json:"value"`
 	Headers   map[string]string `json:"headers,omitempty"`
+	Partition *int32            `json:"partition,omitempty" validate:"omitempty,min=0"`
+	Timestamp int64             `json:"timestamp,omitempty" validate:"min=0"`
+}
+
+// ProduceRequest accepts either a single message at the top level or a
+// batch under "messages", not both. A single message needs a key or value.
+type ProduceRequest struct {
+	ProduceMessage
+	Messages []ProduceMessage `json:"messages,omitempty" validate:"max=1000,dive"`
+}
+
+// Records returns the messages to produce, whichever form was used.
+func (r ProduceRequest) Records() []ProduceMessage {
+	if len(r.Messages) > 0 {
+		return r.Messages
+	}
+	return []ProduceMessage{r.ProduceMessage}
+}
+
+type ProduceResult struct {
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Error     string `json:"error,omitempty"`
 }
 
 // Consume Request
@@ -80,3 +417,131 @@ type ConsumeRequest struct {
 	AutoOffset  string `json:"auto_offset"`  // earliest, latest
 	MaxMessages int    `json:"max_messages"` // 0 = unlimited
 }
+
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEvent records one mutating operation. Before and After hold the
+// relevant state of the resource around the change, where it is known.
+type AuditEvent struct {
+	Time         time.Time `json:"time"`
+	RequestID    string    `json:"request_id,omitempty"`
+	Principal    string    `json:"principal,omitempty"`
+	SourceIP     string    `json:"source_ip"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	Resource     string    `json:"resource"`
+	Before       any       `json:"before,omitempty"`
+	After        any       `json:"after,omitempty"`
+	Outcome      string    `json:"outcome"`
+	Error        string    `json:"error,omitempty"`
+}
+
+type ScramCredential struct {
+	Mechanism  string `json:"mechanism"`
+	Iterations int    `json:"iterations"`
+}
+
+// ScramUser lists the SASL/SCRAM credentials of a user, never the
+// credentials themselves.
+type ScramUser struct {
+	User        string            `json:"user"`
+	Credentials []ScramCredential `json:"credentials"`
+}
+
+// UpsertScramCredentialRequest creates or rotates a credential. The
+// password is generated by the server.
+type UpsertScramCredentialRequest struct {
+	Mechanism  string `json:"mechanism" validate:"omitempty,oneof=SCRAM-SHA-256 SCRAM-SHA-512"`
+	Iterations int    `json:"iterations" validate:"omitempty,min=4096,max=16384"`
+}
+
+// ScramCredentialSecret is returned once when a credential is created or
+// rotated; the password cannot be retrieved later.
+type ScramCredentialSecret struct {
+	User       string `json:"user"`
+	Mechanism  string `json:"mechanism"`
+	Iterations int    `json:"iterations"`
+	Password   string `json:"password"`
+}
+
+// QuotaDefault names the default entity, as kafka-configs --entity-default
+// displays it.
+const QuotaDefault = "<default>"
+
+// QuotaKeys are the client quotas the API manages.
+var QuotaKeys = []string{"producer_byte_rate", "consumer_byte_rate", "request_percentage", "controller_mutation_rate"}
+
+// QuotaEntity identifies a user, a client ID or a user and client ID pair.
+// Either name may be QuotaDefault.
+type QuotaEntity struct {
+	User     string `json:"user,omitempty" query:"user"`
+	ClientID string `json:"client_id,omitempty" query:"client_id"`
+}
+
+func (e QuotaEntity) String() string {
+	var parts []string
+	if e.User != "" {
+		parts = append(parts, "user="+e.User)
+	}
+	if e.ClientID != "" {
+		parts = append(parts, "client-id="+e.ClientID)
+	}
+	return strings.Join(parts, ",")
+}
+
+type ClientQuota struct {
+	Entity QuotaEntity        `json:"entity"`
+	Quotas map[string]float64 `json:"quotas"`
+}
+
+type SetQuotasRequest struct {
+	Entity QuotaEntity        `json:"entity"`
+	Quotas map[string]float64 `json:"quotas" validate:"required,min=1,dive,keys,oneof=producer_byte_rate consumer_byte_rate request_percentage controller_mutation_rate,endkeys,min=0"`
+}
+
+// ACL is one Kafka ACL binding. Enumerations use Kafka's names, e.g.
+// resource type TOPIC, pattern type PREFIXED, operation WRITE and
+// permission ALLOW, and are matched case-insensitively.
+type ACL struct {
+	ResourceType string `json:"resource_type" validate:"required"`
+	ResourceName string `json:"resource_name" validate:"required"`
+	PatternType  string `json:"pattern_type"`
+	Principal    string `json:"principal" validate:"required"`
+	Host         string `json:"host"`
+	Operation    string `json:"operation" validate:"required"`
+	Permission   string `json:"permission"`
+}
+
+// ACLFilter selects ACLs to list or delete. Empty fields match anything.
+type ACLFilter struct {
+	ResourceType string `query:"resource_type"`
+	ResourceName string `query:"resource_name"`
+	PatternType  string `query:"pattern_type"`
+	Principal    string `query:"principal"`
+	Host         string `query:"host"`
+	Operation    string `query:"operation"`
+	Permission   string `query:"permission"`
+}
+
+type CreateACLsRequest struct {
+	ACLs []ACL `json:"acls" validate:"required,min=1,dive"`
+}
+
+const (
+	RoleProducer = "producer"
+	RoleConsumer = "consumer"
+)
+
+// GrantRequest expands into the ACLs a producer or consumer of Topic needs,
+// like the --producer and --consumer options of kafka-acls.
+type GrantRequest struct {
+	Role        string `json:"role" validate:"required,oneof=producer consumer"`
+	Principal   string `json:"principal" validate:"required"`
+	Topic       string `json:"topic" validate:"required"`
+	Group       string `json:"group" validate:"required_if=Role consumer"`
+	PatternType string `json:"pattern_type" validate:"omitempty,oneof=literal prefixed LITERAL PREFIXED"`
+	Host        string `json:"host"`
+}
@@ -0,0 +1,147 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schema types as reported by the registry. Avro schemas are registered
+// without an explicit type.
+const (
+	TypeAvro     = "AVRO"
+	TypeProtobuf = "PROTOBUF"
+	TypeJSON     = "JSON"
+)
+
+type Config struct {
+	URL      string
+	Username string
+	Password string
+}
+
+// Reference points at another registered schema the schema depends on,
+// e.g. an imported .proto file or a named Avro type.
+type Reference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+type Schema struct {
+	ID         int         `json:"-"`
+	Type       string      `json:"schemaType"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references"`
+	// Subjects lists every subject/version the schema is registered under.
+	Subjects []SubjectVersion `json:"-"`
+}
+
+type SubjectVersion struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Client is a minimal Confluent Schema Registry client. Schemas are
+// immutable once registered, so lookups by ID are cached for the lifetime
+// of the client, together with their compiled decoders. Failed lookups are
+// cached for failureTTL, so a topic full of unknown IDs does not send one
+// registry request per message.
+type Client struct {
+	config Config
+	http   *http.Client
+
+	mu       sync.Mutex
+	decoders map[int]*decoder
+	failures map[int]lookupFailure
+}
+
+// lookupFailure is a cached failed schema lookup.
+type lookupFailure struct {
+	err   error
+	until time.Time
+}
+
+const (
+	// lookupTimeout bounds resolving and compiling one schema, including
+	// its references, so decoding a message never waits on the registry
+	// for long.
+	lookupTimeout = 3 * time.Second
+	failureTTL    = time.Minute
+)
+
+func NewClient(cfg Config) *Client {
+	cfg.URL = strings.TrimRight(cfg.URL, "/")
+	return &Client{
+		config:   cfg,
+		http:     &http.Client{Timeout: 10 * time.Second},
+		decoders: make(map[int]*decoder),
+		failures: make(map[int]lookupFailure),
+	}
+}
+
+// SchemaByID fetches the schema registered under id together with the
+// subjects it is registered under.
+func (c *Client) SchemaByID(ctx context.Context, id int) (*Schema, error) {
+	var schema Schema
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d", id), &schema); err != nil {
+		return nil, err
+	}
+	if schema.Type == "" {
+		schema.Type = TypeAvro
+	}
+	schema.ID = id
+
+	if err := c.get(ctx, fmt.Sprintf("/schemas/ids/%d/versions", id), &schema.Subjects); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// schemaBySubject fetches a referenced schema by subject and version.
+func (c *Client) schemaBySubject(ctx context.Context, subject string, version int) (*Schema, error) {
+	var schema Schema
+	path := fmt.Sprintf("/subjects/%s/versions/%d", url.PathEscape(subject), version)
+	if err := c.get(ctx, path, &schema); err != nil {
+		return nil, err
+	}
+	if schema.Type == "" {
+		schema.Type = TypeAvro
+	}
+	return &schema, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+path, nil)
+	if err != nil {
+		return fmt.Errorf("schema registry request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.schemaregistry.v1+json")
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("schema registry request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		return fmt.Errorf("schema registry GET %s: %s %s", path, resp.Status, body.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode schema registry response: %w", err)
+	}
+	return nil
+}
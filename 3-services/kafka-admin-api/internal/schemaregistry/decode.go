@@ -0,0 +1,283 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// The Confluent wire format prefixes payloads with a zero magic byte and
+// the big-endian 4-byte schema ID.
+const (
+	magicByte  = 0x0
+	headerSize = 5
+)
+
+// maxReferenceDepth bounds how deep schema references are followed.
+const maxReferenceDepth = 10
+
+// Decoded is a schema-framed payload decoded to JSON.
+type Decoded struct {
+	SchemaID   int
+	SchemaType string
+	Subject    string
+	Value      json.RawMessage
+}
+
+type decoder struct {
+	schema *Schema
+	decode func(payload []byte) (json.RawMessage, error)
+}
+
+// IsFramed reports whether value carries the Confluent wire-format header.
+func IsFramed(value []byte) bool {
+	return len(value) >= headerSize && value[0] == magicByte
+}
+
+// Decode decodes a wire-format value of topic to JSON. It returns nil and
+// no error when value is not framed.
+func (c *Client) Decode(ctx context.Context, topic string, value []byte) (*Decoded, error) {
+	if !IsFramed(value) {
+		return nil, nil
+	}
+
+	id := int(binary.BigEndian.Uint32(value[1:headerSize]))
+	d, err := c.decoder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := d.decode(value[headerSize:])
+	if err != nil {
+		return nil, fmt.Errorf("decode %s payload with schema %d: %w", d.schema.Type, id, err)
+	}
+
+	return &Decoded{
+		SchemaID:   id,
+		SchemaType: d.schema.Type,
+		Subject:    subjectFor(d.schema.Subjects, topic),
+		Value:      out,
+	}, nil
+}
+
+// subjectFor prefers the subject of the default TopicNameStrategy.
+func subjectFor(subjects []SubjectVersion, topic string) string {
+	for _, s := range subjects {
+		if s.Subject == topic+"-value" {
+			return s.Subject
+		}
+	}
+	if len(subjects) > 0 {
+		return subjects[0].Subject
+	}
+	return ""
+}
+
+func (c *Client) decoder(ctx context.Context, id int) (*decoder, error) {
+	c.mu.Lock()
+	d, ok := c.decoders[id]
+	failure, failed := c.failures[id]
+	c.mu.Unlock()
+	if ok {
+		return d, nil
+	}
+	if failed && time.Now().Before(failure.until) {
+		return nil, failure.err
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	d, err := c.compile(lookupCtx, id)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case err == nil:
+		c.decoders[id] = d
+		delete(c.failures, id)
+	case ctx.Err() == nil:
+		// Only remember failures of the registry or the schema, not ones
+		// caused by the caller giving up.
+		c.failures[id] = lookupFailure{err: err, until: time.Now().Add(failureTTL)}
+	}
+	return d, err
+}
+
+// compile fetches schema id with its references and builds its decoder.
+func (c *Client) compile(ctx context.Context, id int) (*decoder, error) {
+	schema, err := c.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := c.resolveReferences(ctx, schema.References, 0, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	d := &decoder{schema: schema}
+	switch schema.Type {
+	case TypeAvro:
+		d.decode, err = avroDecoder(schema, refs)
+	case TypeProtobuf:
+		d.decode, err = protobufDecoder(ctx, schema, refs)
+	case TypeJSON:
+		d.decode = jsonDecoder
+	default:
+		err = fmt.Errorf("unsupported schema type %s", schema.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compile schema %d: %w", id, err)
+	}
+	return d, nil
+}
+
+type namedSchema struct {
+	name   string
+	schema string
+}
+
+// resolveReferences fetches referenced schemas depth first, so every
+// schema is listed after the schemas it depends on.
+func (c *Client) resolveReferences(ctx context.Context, refs []Reference, depth int, seen map[string]bool) ([]namedSchema, error) {
+	if depth > maxReferenceDepth {
+		return nil, errors.New("schema references nested too deeply")
+	}
+
+	resolved := make([]namedSchema, 0, len(refs))
+	for _, ref := range refs {
+		if seen[ref.Name] {
+			continue
+		}
+		seen[ref.Name] = true
+
+		schema, err := c.schemaBySubject(ctx, ref.Subject, ref.Version)
+		if err != nil {
+			return nil, err
+		}
+		nested, err := c.resolveReferences(ctx, schema.References, depth+1, seen)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, nested...)
+		resolved = append(resolved, namedSchema{name: ref.Name, schema: schema.Schema})
+	}
+	return resolved, nil
+}
+
+func avroDecoder(schema *Schema, refs []namedSchema) (func([]byte) (json.RawMessage, error), error) {
+	cache := &avro.SchemaCache{}
+	for _, ref := range refs {
+		if _, err := avro.ParseWithCache(ref.schema, "", cache); err != nil {
+			return nil, fmt.Errorf("parse reference %s: %w", ref.name, err)
+		}
+	}
+	parsed, err := avro.ParseWithCache(schema.Schema, "", cache)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(payload []byte) (json.RawMessage, error) {
+		var v any
+		if err := avro.Unmarshal(parsed, payload, &v); err != nil {
+			return nil, err
+		}
+		return json.Marshal(v)
+	}, nil
+}
+
+func protobufDecoder(ctx context.Context, schema *Schema, refs []namedSchema) (func([]byte) (json.RawMessage, error), error) {
+	name := fmt.Sprintf("schema-%d.proto", schema.ID)
+	sources := map[string]string{name: schema.Schema}
+	for _, ref := range refs {
+		sources[ref.name] = ref.schema
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	file := files[0]
+
+	return func(payload []byte) (json.RawMessage, error) {
+		indexes, rest, err := messageIndexes(payload)
+		if err != nil {
+			return nil, err
+		}
+		md, err := messageDescriptor(file, indexes)
+		if err != nil {
+			return nil, err
+		}
+
+		msg := dynamicpb.NewMessage(md)
+		if err := proto.Unmarshal(rest, msg); err != nil {
+			return nil, err
+		}
+		return protojson.Marshal(msg)
+	}, nil
+}
+
+// messageIndexes reads the Protobuf message-index path that follows the
+// wire-format header. A single zero count is shorthand for [0], the first
+// message in the file.
+func messageIndexes(payload []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(payload)
+	if n <= 0 || count < 0 {
+		return nil, nil, errors.New("invalid message index count")
+	}
+	payload = payload[n:]
+	if count == 0 {
+		return []int{0}, payload, nil
+	}
+	// Each index takes at least one byte, so a larger count is corrupt and
+	// must not size the allocation.
+	if count > int64(len(payload)) {
+		return nil, nil, fmt.Errorf("message index count %d exceeds the payload size", count)
+	}
+
+	indexes := make([]int, 0, count)
+	for i := int64(0); i < count; i++ {
+		idx, n := binary.Varint(payload)
+		if n <= 0 || idx < 0 {
+			return nil, nil, errors.New("invalid message index")
+		}
+		indexes = append(indexes, int(idx))
+		payload = payload[n:]
+	}
+	return indexes, payload, nil
+}
+
+func messageDescriptor(file protoreflect.FileDescriptor, indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := file.Messages()
+	var md protoreflect.MessageDescriptor
+	for _, idx := range indexes {
+		if idx >= messages.Len() {
+			return nil, fmt.Errorf("message index %d out of range", idx)
+		}
+		md = messages.Get(idx)
+		messages = md.Messages()
+	}
+	return md, nil
+}
+
+func jsonDecoder(payload []byte) (json.RawMessage, error) {
+	if !json.Valid(payload) {
+		return nil, errors.New("payload is not valid JSON")
+	}
+	return json.RawMessage(payload), nil
+}
@@ -0,0 +1,157 @@
+package schemaregistry
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orderAvroSchema = `{
+	"type": "record",
+	"name": "Order",
+	"fields": [
+		{"name": "id", "type": "string"},
+		{"name": "amount", "type": "long"}
+	]
+}`
+
+const orderProtoSchema = `syntax = "proto3";
+package shop;
+
+message Order {
+  string id = 1;
+  int32 quantity = 2;
+}
+`
+
+// newTestRegistry serves a fixed set of schemas and counts lookups by ID.
+func newTestRegistry(t *testing.T, lookups *int32) *httptest.Server {
+	schemas := map[string]map[string]any{
+		"1": {"schema": orderAvroSchema},
+		"2": {"schema": orderProtoSchema, "schemaType": "PROTOBUF"},
+		"3": {"schema": `{"type": "object"}`, "schemaType": "JSON"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schemas/ids/{id}", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(lookups, 1)
+		schema, ok := schemas[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"error_code": 40403, "message": "Schema not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(schema)
+	})
+	mux.HandleFunc("GET /schemas/ids/{id}/versions", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]SubjectVersion{
+			{Subject: "shared-value", Version: 1},
+			{Subject: "orders-value", Version: 3},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func frame(id uint32, payload []byte) []byte {
+	out := make([]byte, headerSize, headerSize+len(payload))
+	binary.BigEndian.PutUint32(out[1:], id)
+	return append(out, payload...)
+}
+
+func TestDecodeAvro(t *testing.T) {
+	var lookups int32
+	client := NewClient(Config{URL: newTestRegistry(t, &lookups).URL})
+
+	schema := avro.MustParse(orderAvroSchema)
+	payload, err := avro.Marshal(schema, map[string]any{"id": "order-1", "amount": int64(250)})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		decoded, err := client.Decode(context.Background(), "orders", frame(1, payload))
+		require.NoError(t, err)
+		assert.Equal(t, 1, decoded.SchemaID)
+		assert.Equal(t, TypeAvro, decoded.SchemaType)
+		assert.Equal(t, "orders-value", decoded.Subject)
+		assert.JSONEq(t, `{"id": "order-1", "amount": 250}`, string(decoded.Value))
+	}
+
+	// The schema is fetched once and served from the cache afterwards.
+	assert.Equal(t, int32(1), atomic.LoadInt32(&lookups))
+}
+
+func TestDecodeProtobuf(t *testing.T) {
+	var lookups int32
+	client := NewClient(Config{URL: newTestRegistry(t, &lookups).URL})
+
+	// Message index [0] encoded as a zero count, then id="abc", quantity=7.
+	payload := []byte{0x00, 0x0a, 0x03, 'a', 'b', 'c', 0x10, 0x07}
+
+	decoded, err := client.Decode(context.Background(), "orders", frame(2, payload))
+	require.NoError(t, err)
+	assert.Equal(t, TypeProtobuf, decoded.SchemaType)
+	assert.JSONEq(t, `{"id": "abc", "quantity": 7}`, string(decoded.Value))
+}
+
+func TestDecodeProtobufRejectsHugeIndexCount(t *testing.T) {
+	var lookups int32
+	client := NewClient(Config{URL: newTestRegistry(t, &lookups).URL})
+
+	payload := binary.AppendVarint(nil, 1<<62)
+	payload = append(payload, 0x02)
+
+	_, err := client.Decode(context.Background(), "orders", frame(2, payload))
+	assert.ErrorContains(t, err, "exceeds the payload size")
+}
+
+func TestDecodeJSONSchema(t *testing.T) {
+	var lookups int32
+	client := NewClient(Config{URL: newTestRegistry(t, &lookups).URL})
+
+	decoded, err := client.Decode(context.Background(), "other", frame(3, []byte(`{"id": "order-1"}`)))
+	require.NoError(t, err)
+	assert.Equal(t, "shared-value", decoded.Subject)
+	assert.JSONEq(t, `{"id": "order-1"}`, string(decoded.Value))
+}
+
+func TestDecodeUnframedAndUnknown(t *testing.T) {
+	var lookups int32
+	client := NewClient(Config{URL: newTestRegistry(t, &lookups).URL})
+
+	decoded, err := client.Decode(context.Background(), "orders", []byte(`{"id": "order-1"}`))
+	assert.NoError(t, err)
+	assert.Nil(t, decoded)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&lookups))
+
+	_, err = client.Decode(context.Background(), "orders", frame(99, []byte("x")))
+	assert.ErrorContains(t, err, "Schema not found")
+}
+
+func TestDecodeCachesFailedLookups(t *testing.T) {
+	var lookups int32
+	client := NewClient(Config{URL: newTestRegistry(t, &lookups).URL})
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Decode(context.Background(), "orders", frame(99, []byte("x")))
+		assert.ErrorContains(t, err, "Schema not found")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&lookups))
+
+	// Failures caused by the caller giving up are not remembered.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := client.Decode(ctx, "orders", frame(1, []byte("x")))
+	assert.Error(t, err)
+	_, ok := client.failures[1]
+	assert.False(t, ok)
+}
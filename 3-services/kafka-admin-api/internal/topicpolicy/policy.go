@@ -0,0 +1,207 @@
+package topicpolicy
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"kafka-admin-api/internal/model"
+)
+
+// Policy constrains the topics that may be created and how they may be
+// changed. Zero fields are not enforced and a nil Policy allows everything.
+type Policy struct {
+	// NamePattern is a regular expression topic names must match in full,
+	// e.g. ^[a-z]+\.[a-z]+\.[a-z0-9-]+\.v[0-9]+$ for domain.team.name.vN.
+	NamePattern   string `yaml:"name_pattern"`
+	MinPartitions int32  `yaml:"min_partitions"`
+	MaxPartitions int32  `yaml:"max_partitions"`
+	// ReplicationFactorMatchesBrokers requires the replication factor to
+	// equal the number of live brokers.
+	ReplicationFactorMatchesBrokers bool     `yaml:"replication_factor_matches_brokers"`
+	MinInsyncReplicas               int      `yaml:"min_insync_replicas"`
+	AllowedCleanupPolicies          []string `yaml:"allowed_cleanup_policies"`
+	// MaxRetentionMs caps retention.ms; it also rules out infinite (-1)
+	// retention.
+	MaxRetentionMs int64 `yaml:"max_retention_ms"`
+
+	namePattern *regexp.Regexp
+}
+
+// Error lists every violation of a request, so callers can fix them all
+// in one go.
+type Error struct {
+	Violations []model.PolicyViolation
+}
+
+func (e *Error) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return "topic policy violated: " + strings.Join(messages, "; ")
+}
+
+// Load reads a YAML policy file:
+//
+//	name_pattern: ^[a-z]+\.[a-z]+\.[a-z0-9-]+\.v[0-9]+$
+//	min_partitions: 3
+//	max_partitions: 48
+//	replication_factor_matches_brokers: true
+//	min_insync_replicas: 2
+//	allowed_cleanup_policies: [delete, compact]
+//	max_retention_ms: 2592000000
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read topic policy file: %w", err)
+	}
+
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse topic policy file %s: %w", path, err)
+	}
+	if err := p.compile(); err != nil {
+		return nil, fmt.Errorf("topic policy file %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) compile() error {
+	if p.NamePattern != "" {
+		re, err := regexp.Compile("^(?:" + p.NamePattern + ")$")
+		if err != nil {
+			return fmt.Errorf("name_pattern: %w", err)
+		}
+		p.namePattern = re
+	}
+	if p.MaxPartitions > 0 && p.MinPartitions > p.MaxPartitions {
+		return fmt.Errorf("min_partitions %d is greater than max_partitions %d", p.MinPartitions, p.MaxPartitions)
+	}
+	return nil
+}
+
+// RequiresBrokerCount reports whether CheckCreate needs the broker count.
+func (p *Policy) RequiresBrokerCount() bool {
+	return p != nil && p.ReplicationFactorMatchesBrokers
+}
+
+// CheckCreate validates a new topic. brokers is the live broker count and
+// is only used when RequiresBrokerCount is true. A new topic must set
+// min.insync.replicas and retention.ms when the policy constrains them, as
+// the broker defaults they would otherwise get are not checked.
+func (p *Policy) CheckCreate(req model.CreateTopicRequest, brokers int) error {
+	if p == nil {
+		return nil
+	}
+	var violations []model.PolicyViolation
+	if p.namePattern != nil && !p.namePattern.MatchString(req.Name) {
+		violations = append(violations, violation("name",
+			"name %q does not match %s", req.Name, p.NamePattern))
+	}
+	violations = append(violations, p.partitionViolations(req.Partitions)...)
+	if p.ReplicationFactorMatchesBrokers && int(req.ReplicationFactor) != brokers {
+		violations = append(violations, violation("replication_factor",
+			"replication factor must equal the broker count %d, got %d", brokers, req.ReplicationFactor))
+	}
+	violations = append(violations, p.missingConfigViolations(req.Configs)...)
+	violations = append(violations, p.configViolations(req.Configs)...)
+	return asError(violations)
+}
+
+// missingConfigViolations reports constrained configs a new topic leaves
+// unset.
+func (p *Policy) missingConfigViolations(configs map[string]string) []model.PolicyViolation {
+	var violations []model.PolicyViolation
+	if _, ok := configs["min.insync.replicas"]; !ok && p.MinInsyncReplicas > 0 {
+		violations = append(violations, violation("configs.min.insync.replicas",
+			"min.insync.replicas is required and must be at least %d", p.MinInsyncReplicas))
+	}
+	if _, ok := configs["retention.ms"]; !ok && p.MaxRetentionMs > 0 {
+		violations = append(violations, violation("configs.retention.ms",
+			"retention.ms is required and must be between 0 and %d", p.MaxRetentionMs))
+	}
+	return violations
+}
+
+// CheckConfigs validates config changes to an existing topic. Configs left
+// unset fall back to broker defaults and are not checked.
+func (p *Policy) CheckConfigs(configs map[string]string) error {
+	if p == nil {
+		return nil
+	}
+	return asError(p.configViolations(configs))
+}
+
+// CheckPartitions validates a new partition count of an existing topic.
+func (p *Policy) CheckPartitions(count int32) error {
+	if p == nil {
+		return nil
+	}
+	return asError(p.partitionViolations(count))
+}
+
+func (p *Policy) partitionViolations(count int32) []model.PolicyViolation {
+	var violations []model.PolicyViolation
+	if p.MinPartitions > 0 && count < p.MinPartitions {
+		violations = append(violations, violation("partitions",
+			"partitions must be at least %d, got %d", p.MinPartitions, count))
+	}
+	if p.MaxPartitions > 0 && count > p.MaxPartitions {
+		violations = append(violations, violation("partitions",
+			"partitions must be at most %d, got %d", p.MaxPartitions, count))
+	}
+	return violations
+}
+
+func (p *Policy) configViolations(configs map[string]string) []model.PolicyViolation {
+	var violations []model.PolicyViolation
+
+	if v, ok := configs["min.insync.replicas"]; ok && p.MinInsyncReplicas > 0 {
+		if n, err := strconv.Atoi(v); err != nil || n < p.MinInsyncReplicas {
+			violations = append(violations, violation("configs.min.insync.replicas",
+				"min.insync.replicas must be at least %d, got %q", p.MinInsyncReplicas, v))
+		}
+	}
+
+	if v, ok := configs["cleanup.policy"]; ok && len(p.AllowedCleanupPolicies) > 0 {
+		for _, policy := range strings.Split(v, ",") {
+			if !contains(p.AllowedCleanupPolicies, strings.TrimSpace(policy)) {
+				violations = append(violations, violation("configs.cleanup.policy",
+					"cleanup.policy %q is not one of %s", policy, strings.Join(p.AllowedCleanupPolicies, ", ")))
+			}
+		}
+	}
+
+	if v, ok := configs["retention.ms"]; ok && p.MaxRetentionMs > 0 {
+		if ms, err := strconv.ParseInt(v, 10, 64); err != nil || ms < 0 || ms > p.MaxRetentionMs {
+			violations = append(violations, violation("configs.retention.ms",
+				"retention.ms must be between 0 and %d, got %q", p.MaxRetentionMs, v))
+		}
+	}
+	return violations
+}
+
+func violation(field, format string, args ...any) model.PolicyViolation {
+	return model.PolicyViolation{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func asError(violations []model.PolicyViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &Error{Violations: violations}
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package topicpolicy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"kafka-admin-api/internal/model"
+)
+
+func loadPolicy(t *testing.T, content string) *Policy {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "topic-policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	p, err := Load(path)
+	require.NoError(t, err)
+	return p
+}
+
+const testPolicy = `
+name_pattern: '[a-z]+\.[a-z]+\.[a-z0-9-]+\.v[0-9]+'
+min_partitions: 3
+max_partitions: 12
+replication_factor_matches_brokers: true
+min_insync_replicas: 2
+allowed_cleanup_policies: [delete, compact]
+max_retention_ms: 2592000000
+`
+
+func violationFields(t *testing.T, err error) []string {
+	t.Helper()
+	var perr *Error
+	require.True(t, errors.As(err, &perr), "expected a policy error, got %v", err)
+	fields := make([]string, len(perr.Violations))
+	for i, v := range perr.Violations {
+		fields[i] = v.Field
+	}
+	return fields
+}
+
+func TestCheckCreate(t *testing.T) {
+	p := loadPolicy(t, testPolicy)
+
+	valid := model.CreateTopicRequest{
+		Name:              "sales.orders.placed.v1",
+		Partitions:        6,
+		ReplicationFactor: 3,
+		Configs: map[string]string{
+			"min.insync.replicas": "2",
+			"cleanup.policy":      "compact,delete",
+			"retention.ms":        "604800000",
+		},
+	}
+	assert.NoError(t, p.CheckCreate(valid, 3))
+
+	defaults := valid
+	defaults.Configs = map[string]string{"cleanup.policy": "delete"}
+	assert.ElementsMatch(t, []string{
+		"configs.min.insync.replicas",
+		"configs.retention.ms",
+	}, violationFields(t, p.CheckCreate(defaults, 3)), "constrained configs left to the broker default are rejected")
+
+	invalid := model.CreateTopicRequest{
+		Name:              "orders",
+		Partitions:        1,
+		ReplicationFactor: 1,
+		Configs: map[string]string{
+			"min.insync.replicas": "1",
+			"cleanup.policy":      "delete,archive",
+			"retention.ms":        "-1",
+		},
+	}
+	err := p.CheckCreate(invalid, 3)
+	assert.ElementsMatch(t, []string{
+		"name",
+		"partitions",
+		"replication_factor",
+		"configs.min.insync.replicas",
+		"configs.cleanup.policy",
+		"configs.retention.ms",
+	}, violationFields(t, err), "every violation is reported at once")
+}
+
+func TestNamePatternMatchesWholeName(t *testing.T) {
+	p := loadPolicy(t, "name_pattern: '[a-z]+\\.v[0-9]+'\n")
+	assert.NoError(t, p.CheckCreate(model.CreateTopicRequest{Name: "orders.v1"}, 0))
+	assert.Error(t, p.CheckCreate(model.CreateTopicRequest{Name: "x.orders.v1.tmp"}, 0))
+}
+
+func TestCheckConfigsAndPartitions(t *testing.T) {
+	p := loadPolicy(t, testPolicy)
+
+	assert.NoError(t, p.CheckConfigs(map[string]string{"retention.ms": "86400000"}))
+	assert.Equal(t, []string{"configs.retention.ms"},
+		violationFields(t, p.CheckConfigs(map[string]string{"retention.ms": "9999999999999"})))
+
+	assert.NoError(t, p.CheckPartitions(12))
+	assert.Equal(t, []string{"partitions"}, violationFields(t, p.CheckPartitions(24)))
+}
+
+func TestNilPolicyAllowsEverything(t *testing.T) {
+	var p *Policy
+	assert.False(t, p.RequiresBrokerCount())
+	assert.NoError(t, p.CheckCreate(model.CreateTopicRequest{Name: "anything"}, 0))
+	assert.NoError(t, p.CheckConfigs(map[string]string{"retention.ms": "-1"}))
+	assert.NoError(t, p.CheckPartitions(1000))
+}
+
+func TestLoadRejectsInvalidPolicy(t *testing.T) {
+	tests := map[string]string{
+		"bad pattern":      "name_pattern: '[a-z'\n",
+		"min above max":    "min_partitions: 12\nmax_partitions: 6\n",
+		"not a yaml value": "min_partitions: [1]\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "topic-policy.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+}